@@ -0,0 +1,199 @@
+// Package archive keeps the raw wikitable2json responses we fetched, so the
+// parsed data can be regenerated later without going back to the network.
+//
+// Bodies are gzipped and stored by the sha256 of their uncompressed contents
+// under objects/, and every fetch is recorded in index.jsonl along with the
+// time it was made.
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const indexFilename = "index.jsonl"
+
+type Entry struct {
+	Url        string
+	Hash       string
+	RevisionId int64 `json:",omitempty"`
+	FetchedAt  time.Time
+}
+
+type Archive struct {
+	Dir string
+}
+
+func Open(dir string) *Archive {
+	return &Archive{Dir: dir}
+}
+
+func Hash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// validHash checks hash looks like something Hash returned, so it's safe to
+// build an object path from
+func validHash(hash string) error {
+	if len(hash) != sha256.Size*2 {
+		return fmt.Errorf("invalid hash %q, expected %d hex digits", hash, sha256.Size*2)
+	}
+	if _, err := hex.DecodeString(hash); err != nil {
+		return fmt.Errorf("invalid hash %q: %w", hash, err)
+	}
+	return nil
+}
+
+func (a *Archive) objectPath(hash string) string {
+	return filepath.Join(a.Dir, "objects", hash[:2], hash+".json.gz")
+}
+
+// Put stores body if we haven't seen it before, and records the fetch in the
+// index either way. The entry's Hash is filled in from body.
+func (a *Archive) Put(entry Entry, body []byte) (Entry, error) {
+	entry.Hash = Hash(body)
+	entry.FetchedAt = entry.FetchedAt.UTC()
+
+	objectPath := a.objectPath(entry.Hash)
+	if _, err := os.Stat(objectPath); errors.Is(err, os.ErrNotExist) {
+		if err := writeCompressed(objectPath, body); err != nil {
+			return entry, err
+		}
+	} else if err != nil {
+		return entry, err
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return entry, err
+	}
+
+	index, err := os.OpenFile(filepath.Join(a.Dir, indexFilename), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return entry, err
+	}
+	defer index.Close()
+
+	_, err = index.Write(append(line, '\n'))
+	return entry, err
+}
+
+func writeCompressed(filename string, body []byte) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so an interrupted run can't leave a
+	// truncated object behind under a valid hash
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+// Get returns the uncompressed body stored under hash
+func (a *Archive) Get(hash string) ([]byte, error) {
+	if err := validHash(hash); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(a.objectPath(hash))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading archived object %s: %w", hash, err)
+	}
+	defer zr.Close()
+
+	body, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("reading archived object %s: %w", hash, err)
+	}
+
+	if got := Hash(body); got != hash {
+		return nil, fmt.Errorf("archived object %s is corrupt, contents hash to %s", hash, got)
+	}
+
+	return body, nil
+}
+
+// Entries returns every recorded fetch, oldest first
+func (a *Archive) Entries() ([]Entry, error) {
+	f, err := os.Open(filepath.Join(a.Dir, indexFilename))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", indexFilename, line, err)
+		}
+		if err := validHash(entry.Hash); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", indexFilename, line, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+// Latest finds the most recent fetch of url made at or before asOf. A zero
+// asOf means "the most recent fetch we have".
+func (a *Archive) Latest(url string, asOf time.Time) (Entry, bool, error) {
+	entries, err := a.Entries()
+	if err != nil {
+		return Entry{}, false, err
+	}
+
+	var latest Entry
+	found := false
+	for _, entry := range entries {
+		if entry.Url != url {
+			continue
+		}
+		if !asOf.IsZero() && entry.FetchedAt.After(asOf) {
+			continue
+		}
+		if !found || !entry.FetchedAt.Before(latest.FetchedAt) {
+			latest = entry
+			found = true
+		}
+	}
+
+	return latest, found, nil
+}
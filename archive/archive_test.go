@@ -0,0 +1,85 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPutAndGet(t *testing.T) {
+	a := Open(t.TempDir())
+	body := []byte(`[[["6 January21:49:10[1]", ""]]]`)
+
+	entry, err := a.Put(Entry{Url: "https://example.com/2022", FetchedAt: time.Now()}, body)
+	require.NoError(t, err)
+	assert.Equal(t, Hash(body), entry.Hash)
+
+	got, err := a.Get(entry.Hash)
+	require.NoError(t, err)
+	assert.Equal(t, body, got)
+}
+
+func TestLatestRespectsAsOf(t *testing.T) {
+	a := Open(t.TempDir())
+	url := "https://example.com/2022"
+	first := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	old, err := a.Put(Entry{Url: url, FetchedAt: first}, []byte(`["old"]`))
+	require.NoError(t, err)
+	newest, err := a.Put(Entry{Url: url, FetchedAt: second}, []byte(`["new"]`))
+	require.NoError(t, err)
+	_, err = a.Put(Entry{Url: "https://example.com/2021", FetchedAt: second}, []byte(`["other"]`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		asOf  time.Time
+		want  Entry
+		found bool
+	}{
+		{time.Time{}, newest, true},
+		{second, newest, true},
+		{second.Add(-time.Hour), old, true},
+		{first.Add(-time.Hour), Entry{}, false},
+	}
+
+	for _, test := range tests {
+		got, found, err := a.Latest(url, test.asOf)
+		require.NoError(t, err)
+		assert.Equal(t, test.found, found)
+		assert.Equal(t, test.want, got)
+	}
+}
+
+func TestRepeatedFetchesAreAllRecorded(t *testing.T) {
+	a := Open(t.TempDir())
+	body := []byte(`["same"]`)
+
+	for i := 0; i < 3; i++ {
+		_, err := a.Put(Entry{Url: "https://example.com/2022", FetchedAt: time.Now()}, body)
+		require.NoError(t, err)
+	}
+
+	entries, err := a.Entries()
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+}
+
+func TestInvalidHashesAreRejected(t *testing.T) {
+	a := Open(t.TempDir())
+	index := `{"Url":"https://example.com/2022","Hash":"a","FetchedAt":"2022-08-01T00:00:00Z"}` + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(a.Dir, indexFilename), []byte(index), 0o644))
+
+	_, _, err := a.Latest("https://example.com/2022", time.Time{})
+	assert.ErrorContains(t, err, "line 1")
+
+	for _, hash := range []string{"", "a", strings.Repeat("z", 64)} {
+		_, err := a.Get(hash)
+		assert.ErrorContains(t, err, "invalid hash", hash)
+	}
+}
@@ -0,0 +1,192 @@
+// Package boosters follows reusable boosters from flight to flight, using the
+// serials and landings merged in from the vehicle lists, to answer questions
+// like which booster is the fleet leader and how quickly they're turned around.
+package boosters
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"launchdata/parse"
+)
+
+// Flight is one launch a booster flew on
+type Flight struct {
+	LaunchId       string
+	FlightNumber   string
+	Rocket         string
+	Timestamp      time.Time
+	LandingType    string `json:",omitempty"`
+	LandingOutcome string `json:",omitempty"`
+
+	// TurnaroundDays is the time since the booster's previous flight, 0 for its
+	// first
+	TurnaroundDays float64
+}
+
+// LandingAttempted reports whether the booster tried to land. "No attempt"
+// and "Precluded" mean it was expended.
+func (f Flight) LandingAttempted() bool {
+	outcome := strings.ToLower(f.LandingOutcome)
+	return outcome != "" && outcome != "no attempt" && outcome != "precluded"
+}
+
+func (f Flight) Landed() bool {
+	return strings.EqualFold(f.LandingOutcome, "success")
+}
+
+type Booster struct {
+	Serial string
+	// Flights are in the order they were flown
+	Flights []Flight
+}
+
+func (b Booster) FirstFlight() Flight {
+	return b.Flights[0]
+}
+
+func (b Booster) LastFlight() Flight {
+	return b.Flights[len(b.Flights)-1]
+}
+
+// Landings counts the landings the booster attempted and the ones it made
+func (b Booster) Landings() (attempted int, landed int) {
+	for _, flight := range b.Flights {
+		if flight.LandingAttempted() {
+			attempted++
+		}
+		if flight.Landed() {
+			landed++
+		}
+	}
+	return attempted, landed
+}
+
+func (b Booster) turnarounds() []float64 {
+	var days []float64
+	for _, flight := range b.Flights[1:] {
+		days = append(days, flight.TurnaroundDays)
+	}
+	return days
+}
+
+// MedianTurnaroundDays is 0 for a booster that has only flown once
+func (b Booster) MedianTurnaroundDays() float64 {
+	return median(b.turnarounds())
+}
+
+// Fleet is every booster we've seen fly
+type Fleet struct {
+	// Boosters are ordered by the number of flights, most flown first
+	Boosters []Booster
+}
+
+// Build collects the flights of each booster from launches that have already
+// happened by now. Launches are matched to a booster by serial, and kept
+// apart from each other by FlightNumber, so the same launch appearing in two
+// cached files isn't counted twice.
+func Build(launches []parse.RocketData, now time.Time) Fleet {
+	bySerial := map[string]*Booster{}
+	seen := map[string]bool{}
+
+	for _, launch := range launches {
+		if !launch.Timestamp.LaunchedAlready(now) {
+			continue
+		}
+
+		for _, booster := range launch.Boosters {
+			key := booster.Serial + "\x00" + strings.TrimSpace(launch.FlightNumber) + "\x00" + launch.Timestamp.DateString()
+			if booster.Serial == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			b, ok := bySerial[booster.Serial]
+			if !ok {
+				b = &Booster{Serial: booster.Serial}
+				bySerial[booster.Serial] = b
+			}
+			b.Flights = append(b.Flights, Flight{
+				LaunchId:       launch.Id,
+				FlightNumber:   strings.TrimSpace(launch.FlightNumber),
+				Rocket:         strings.TrimSpace(launch.Rocket),
+				Timestamp:      launch.Timestamp.Timestamp,
+				LandingType:    booster.LandingType,
+				LandingOutcome: booster.LandingOutcome,
+			})
+		}
+	}
+
+	var fleet Fleet
+	for _, b := range bySerial {
+		sort.SliceStable(b.Flights, func(i, j int) bool {
+			return b.Flights[i].Timestamp.Before(b.Flights[j].Timestamp)
+		})
+		for i := 1; i < len(b.Flights); i++ {
+			b.Flights[i].TurnaroundDays = b.Flights[i].Timestamp.Sub(b.Flights[i-1].Timestamp).Hours() / 24
+		}
+		fleet.Boosters = append(fleet.Boosters, *b)
+	}
+
+	sort.Slice(fleet.Boosters, func(i, j int) bool {
+		a, b := fleet.Boosters[i], fleet.Boosters[j]
+		if len(a.Flights) != len(b.Flights) {
+			return len(a.Flights) > len(b.Flights)
+		}
+		return a.Serial < b.Serial
+	})
+
+	return fleet
+}
+
+func (f Fleet) Get(serial string) (Booster, bool) {
+	for _, b := range f.Boosters {
+		if strings.EqualFold(b.Serial, serial) {
+			return b, true
+		}
+	}
+	return Booster{}, false
+}
+
+// Leader is the booster with the most flights. Ties go to the one that
+// reached that number first, which is how the fleet leader is usually called.
+func (f Fleet) Leader() (Booster, bool) {
+	if len(f.Boosters) == 0 {
+		return Booster{}, false
+	}
+
+	leader := f.Boosters[0]
+	for _, b := range f.Boosters[1:] {
+		if len(b.Flights) < len(leader.Flights) {
+			break
+		}
+		if b.LastFlight().Timestamp.Before(leader.LastFlight().Timestamp) {
+			leader = b
+		}
+	}
+	return leader, true
+}
+
+// MedianTurnaroundDays is the median time between flights across every
+// booster that has been reflown
+func (f Fleet) MedianTurnaroundDays() float64 {
+	var days []float64
+	for _, b := range f.Boosters {
+		days = append(days, b.turnarounds()...)
+	}
+	return median(days)
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	if len(sorted)%2 == 1 {
+		return sorted[len(sorted)/2]
+	}
+	return (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+}
@@ -0,0 +1,70 @@
+package boosters
+
+import (
+	"testing"
+	"time"
+
+	"launchdata/parse"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func launch(flightNumber string, day int, boosters ...parse.BoosterData) parse.RocketData {
+	return parse.RocketData{
+		Id:           "falcon-9/" + flightNumber,
+		Rocket:       "Falcon 9 Block 5",
+		FlightNumber: flightNumber,
+		Timestamp:    parse.TimeData{Timestamp: time.Date(2023, 1, day, 12, 0, 0, 0, time.UTC), ParsedOk: true},
+		Boosters:     boosters,
+	}
+}
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+	launches := []parse.RocketData{
+		launch("3", 21, parse.BoosterData{Serial: "B1060", LandingOutcome: "Success", LandingType: "OCISLY"}),
+		launch("1", 1, parse.BoosterData{Serial: "B1060", LandingOutcome: "Failure", LandingType: "JRTI"}),
+		launch("2", 11, parse.BoosterData{Serial: "B1062", LandingOutcome: "Success"}),
+		launch("4", 25, parse.BoosterData{Serial: "B1062", LandingOutcome: "No attempt"}),
+		// The same launch from another file
+		launch("1", 1, parse.BoosterData{Serial: "B1060", LandingOutcome: "Failure", LandingType: "JRTI"}),
+		// Not flown yet
+		launch("5", 28, parse.BoosterData{Serial: "B1060"}),
+	}
+	launches[5].Timestamp.Timestamp = now.Add(time.Hour)
+
+	fleet := Build(launches, now)
+	require.Len(t, fleet.Boosters, 2)
+
+	b1060, ok := fleet.Get("b1060")
+	require.True(t, ok)
+	require.Len(t, b1060.Flights, 2)
+	assert.Equal(t, "1", b1060.FirstFlight().FlightNumber)
+	assert.Equal(t, "3", b1060.LastFlight().FlightNumber)
+	assert.Equal(t, 20.0, b1060.LastFlight().TurnaroundDays)
+	assert.Equal(t, 20.0, b1060.MedianTurnaroundDays())
+
+	attempted, landed := b1060.Landings()
+	assert.Equal(t, 2, attempted)
+	assert.Equal(t, 1, landed)
+
+	b1062, _ := fleet.Get("B1062")
+	attempted, landed = b1062.Landings()
+	assert.Equal(t, 1, attempted)
+	assert.Equal(t, 1, landed)
+
+	// Both have flown twice, but B1062 got there last
+	leader, ok := fleet.Leader()
+	require.True(t, ok)
+	assert.Equal(t, "B1060", leader.Serial)
+
+	assert.Equal(t, 17.0, fleet.MedianTurnaroundDays())
+}
+
+func TestEmptyFleet(t *testing.T) {
+	fleet := Build(nil, time.Now())
+	_, ok := fleet.Leader()
+	assert.False(t, ok)
+	assert.Equal(t, 0.0, fleet.MedianTurnaroundDays())
+}
@@ -1,11 +1,13 @@
 package bubble
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"launchdata/cli"
 	"launchdata/config"
+	"launchdata/dataset"
 	"launchdata/parse"
 	"launchdata/slices"
 
@@ -37,6 +39,8 @@ var (
 			Padding(0, 1)
 
 	listWrapperStyle = lipgloss.NewStyle().Width(80).BorderStyle(lipgloss.HiddenBorder())
+
+	problemStyle = lipgloss.NewStyle().Padding(0, 1)
 )
 
 type listKeyMap struct {
@@ -47,6 +51,7 @@ type listKeyMap struct {
 	toggleHelpMenu   key.Binding
 	insertItem       key.Binding
 	switchFocus      key.Binding
+	switchView       key.Binding
 	quit             key.Binding
 	forceQuit        key.Binding
 }
@@ -81,6 +86,10 @@ func newListKeyMap() *listKeyMap {
 			key.WithKeys("tab"),
 			key.WithHelp("tab", "switch focus"),
 		),
+		switchView: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "launches/rendezvous/EVAs"),
+		),
 		// Quitting.
 		quit: key.NewBinding(
 			key.WithKeys("q", "esc"),
@@ -90,40 +99,80 @@ func newListKeyMap() *listKeyMap {
 	}
 }
 
+// view is one of the tables from a year that can be browsed
+type view struct {
+	name  string
+	items []MyItem
+}
+
 type model struct {
-	items        []MyItem
+	year         int
+	views        []view
+	currentView  int
 	list         list.Model[MyItem]
 	listFocused  bool
 	viewport     viewport.Model
 	keys         *listKeyMap
 	delegateKeys *delegateKeyMap
+
+	// problem is shown instead of the list when there's nothing to browse
+	problem string
 }
 
-func newModel(year int) model {
+func newModel(config *config.Config, year int) model {
 	var (
 		delegateKeys = newDelegateKeyMap()
 		listKeys     = newListKeyMap()
 	)
-	entries, err := parse.LoadLaunchDataFromFile(fmt.Sprintf("./data/launchdata-%d.json", year))
+	filename := dataset.Filename(config.DataDir, year)
+	entries, err := parse.LoadLaunchData(filename, parse.LoadOptions{Strict: config.Strict})
 	if err != nil {
-		panic(err)
+		return model{keys: listKeys, problem: describeLoadError(err, year)}
+	}
+	if len(entries.OrbitalFlights) == 0 {
+		return model{keys: listKeys, problem: fmt.Sprintf(
+			"No launches were parsed for %d.\n\n"+
+				"%s loaded fine, but it's empty. The parser probably doesn't\n"+
+				"understand that year's wikipedia page yet.", year, filename)}
 	}
 
-	var items []MyItem
+	views := []view{{name: "Launches"}}
 	for _, r := range entries.OrbitalFlights {
-		items = append(items, MyItem{data: r})
+		views[0].items = append(views[0].items, launchItem(r))
+	}
+	slices.Reverse(views[0].items)
+
+	// Older files, and years without any, don't have these
+	if len(entries.Rendezvous) > 0 {
+		v := view{name: "Deep space rendezvous"}
+		for _, r := range entries.Rendezvous {
+			v.items = append(v.items, rendezvousItem(r))
+		}
+		views = append(views, v)
+	}
+	if len(entries.Evas) > 0 {
+		v := view{name: "EVAs"}
+		for _, e := range entries.Evas {
+			v.items = append(v.items, evaItem(e))
+		}
+		views = append(views, v)
 	}
-	slices.Reverse(items)
 
 	width, height, _ := term.GetSize(0)
 	height = height - 5
 	listWidth := int(float32(width) * 0.5)
 
 	delegate := newItemDelegate(delegateKeys)
-	l := list.New[MyItem](items, delegate, listWidth, height)
-	l.Title = fmt.Sprintf("Launches in %d", year)
+	l := list.New[MyItem](views[0].items, delegate, listWidth, height)
+	l.Title = fmt.Sprintf("%s in %d", views[0].name, year)
 	l.Styles.Title = titleStyle
 	l.DisableQuitKeybindings()
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		if len(views) < 2 {
+			return nil
+		}
+		return []key.Binding{listKeys.switchView}
+	}
 	l.AdditionalFullHelpKeys = func() []key.Binding {
 		return []key.Binding{
 			listKeys.toggleSpinner,
@@ -139,7 +188,8 @@ func newModel(year int) model {
 	viewport.Style = viewportStyle
 
 	return model{
-		items:        items,
+		year:         year,
+		views:        views,
 		list:         l,
 		listFocused:  true,
 		viewport:     viewport,
@@ -152,9 +202,32 @@ func (m model) Init() tea.Cmd {
 	return tea.EnterAltScreen
 }
 
+func describeLoadError(err error, year int) string {
+	var hint string
+	switch {
+	case errors.Is(err, parse.ErrMissingFile):
+		hint = fmt.Sprintf("%d hasn't been cached yet, try:\n\n  launchdata cache all", year)
+	case errors.Is(err, parse.ErrMalformedJson):
+		hint = "The file looks corrupt or truncated. Caching it again should fix it."
+	case errors.Is(err, parse.ErrSchemaMismatch):
+		hint = "The file was written by a different version of launchdata. If it's\n" +
+			"older, try:\n\n  launchdata migrate"
+	}
+
+	return fmt.Sprintf("Couldn't load the launches for %d.\n\n%v\n\n%s", year, err, hint)
+}
+
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
+	if m.problem != "" {
+		// Any key gets us out of the problem screen
+		if _, ok := msg.(tea.KeyMsg); ok {
+			return m, tea.Quit
+		}
+		return m, nil
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		h, v := appStyle.GetFrameSize()
@@ -195,6 +268,23 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, m.keys.switchFocus):
 			m.listFocused = !m.listFocused
 
+		case key.Matches(msg, m.keys.switchView):
+			if len(m.views) < 2 {
+				break
+			}
+			m.currentView = (m.currentView + 1) % len(m.views)
+			current := m.views[m.currentView]
+			m.list.ResetFilter()
+			cmds = append(cmds, m.list.SetItems(current.items))
+			m.list.ResetSelected()
+			m.list.Title = fmt.Sprintf("%s in %d", current.name, m.year)
+			m.listFocused = true
+			if i := m.list.SelectedItem(); i != nil {
+				m.viewport.SetContent(i.Render(80))
+			}
+			m.viewport.GotoTop()
+			return m, tea.Batch(cmds...)
+
 		case key.Matches(msg, m.keys.forceQuit):
 			return m, tea.Quit
 
@@ -225,6 +315,13 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m model) View() string {
+	if m.problem != "" {
+		return appStyle.Render(
+			titleStyle.Render("Launchdata") + "\n\n" +
+				problemStyle.Render(m.problem) + "\n\n" +
+				statusMessageStyle("Press any key to quit"))
+	}
+
 	if m.listFocused {
 		listWrapperStyle.BorderStyle(lipgloss.RoundedBorder())
 		m.viewport.Style.BorderStyle(lipgloss.HiddenBorder())
@@ -242,7 +339,7 @@ func (m model) View() string {
 func Run(config *config.Config, year int) {
 	cli.ClearScreen()
 
-	p := tea.NewProgram(newModel(year), tea.WithAltScreen())
+	p := tea.NewProgram(newModel(config, year), tea.WithAltScreen())
 
 	if err := p.Start(); err != nil {
 		fmt.Println("Error running program:", err)
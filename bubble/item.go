@@ -2,28 +2,53 @@ package bubble
 
 import (
 	"fmt"
+	"strings"
 
+	"launchdata/jsonio"
 	"launchdata/list"
 	"launchdata/parse"
 
 	"github.com/charmbracelet/lipgloss"
 )
 
+// MyItem is a row in the list, which can be a launch, a deep space rendezvous
+// or an EVA
 type MyItem struct {
-	data parse.RocketData
+	title       string
+	description string
+	data        interface{}
+}
+
+func launchItem(r parse.RocketData) MyItem {
+	return MyItem{
+		title:       fmt.Sprintf("%s (%s)", r.Rocket, r.FlightNumber),
+		description: fmt.Sprintf("%v, %s, %s", r.Timestamp.DateString(), r.LaunchServiceProvider, r.LaunchSite),
+		data:        r,
+	}
+}
+
+func rendezvousItem(r parse.Rendezvous) MyItem {
+	return MyItem{
+		title:       r.Spacecraft,
+		description: fmt.Sprintf("%v, %s", r.Date.DateString(), r.Event),
+		data:        r,
+	}
+}
+
+func evaItem(e parse.Eva) MyItem {
+	return MyItem{
+		title:       fmt.Sprintf("%s (%s)", e.Spacecraft, e.Duration),
+		description: fmt.Sprintf("%v, %s", e.Start.DateString(), strings.Join(e.Crew, ", ")),
+		data:        e,
+	}
 }
 
 func (i MyItem) Title() string {
-	return fmt.Sprintf("%s (%s)",
-		i.data.Rocket,
-		i.data.FlightNumber)
+	return i.title
 }
 
 func (i MyItem) Description() string {
-	return fmt.Sprintf("%v, %s, %s",
-		i.data.Timestamp.DateString(),
-		i.data.LaunchServiceProvider,
-		i.data.LaunchSite)
+	return i.description
 }
 
 func (i MyItem) FilterValue() string {
@@ -31,7 +56,11 @@ func (i MyItem) FilterValue() string {
 }
 
 func (i MyItem) Render(width int) string {
-	return lipgloss.NewStyle().Width(80).Render(i.data.Render())
+	formatted, err := jsonio.FormattedJson(i.data)
+	if err != nil {
+		fmt.Println(fmt.Errorf("error when formatting json: %v", err))
+	}
+	return lipgloss.NewStyle().Width(80).Render(formatted.String())
 }
 
 var _ list.Item = (*MyItem)(nil)
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"launchdata/boosters"
+	"launchdata/config"
+	"launchdata/dataset"
+	"launchdata/jsonio"
+
+	"github.com/spf13/cobra"
+)
+
+func boostersCmd() *cobra.Command {
+	var asJson bool
+
+	cmdBoosters := &cobra.Command{
+		Use:   "boosters [flags] [serial]",
+		Short: "Show how reusable boosters have been flown and landed",
+		Long: `Show every booster that has flown, with its number of flights, landings and
+median turnaround between flights, along with the fleet leader. Given a
+serial, e.g. B1060, show each of that booster's flights instead.
+
+Boosters are only known for rockets that have a vehicle list, which cache
+reads along with the yearly pages.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := config.Init(cmd)
+			data, err := dataset.LoadAll(config.DataDir, loadOptions(config))
+			if err != nil {
+				return err
+			}
+
+			fleet := boosters.Build(data.OrbitalFlights, time.Now())
+			if len(fleet.Boosters) == 0 {
+				return fmt.Errorf("no boosters found in %s, try caching a year with a vehicle list, e.g. \"launchdata cache --year 2023\"", config.DataDir)
+			}
+
+			if len(args) == 1 {
+				booster, ok := fleet.Get(args[0])
+				if !ok {
+					return fmt.Errorf("no flights found for booster %s", args[0])
+				}
+				if asJson {
+					return printJson(booster)
+				}
+				printBoosterFlights(booster)
+				return nil
+			}
+
+			if asJson {
+				return printJson(fleet)
+			}
+			printFleet(fleet)
+			return nil
+		},
+	}
+	cmdBoosters.Flags().BoolVar(&asJson, "json", false, "print json instead of a table")
+
+	return cmdBoosters
+}
+
+func printJson(contents interface{}) error {
+	formatted, err := jsonio.FormattedJson(contents)
+	if err != nil {
+		return err
+	}
+	fmt.Println(formatted.String())
+	return nil
+}
+
+func printFleet(fleet boosters.Fleet) {
+	if leader, ok := fleet.Leader(); ok {
+		fmt.Printf("Fleet leader: %s, %d flights\n", leader.Serial, len(leader.Flights))
+	}
+	fmt.Printf("Median turnaround: %.1f days\n\n", fleet.MedianTurnaroundDays())
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "Booster\tFlights\tLandings\tFirst flight\tLast flight\tMedian turnaround days\t")
+	for _, b := range fleet.Boosters {
+		attempted, landed := b.Landings()
+		fmt.Fprintf(w, "%s\t%d\t%d/%d\t%s\t%s\t%.1f\t\n",
+			b.Serial, len(b.Flights), landed, attempted,
+			b.FirstFlight().Timestamp.Format("2006-01-02"), b.LastFlight().Timestamp.Format("2006-01-02"),
+			b.MedianTurnaroundDays())
+	}
+	w.Flush()
+}
+
+func printBoosterFlights(booster boosters.Booster) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "Date\tRocket\tFlight\tLanding\tTurnaround days\t")
+	for _, flight := range booster.Flights {
+		landing := flight.LandingOutcome
+		if flight.LandingType != "" {
+			landing = fmt.Sprintf("%s (%s)", landing, flight.LandingType)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.1f\t\n",
+			flight.Timestamp.Format("2006-01-02"), flight.Rocket, flight.FlightNumber, landing, flight.TurnaroundDays)
+	}
+	w.Flush()
+}
@@ -1,9 +1,9 @@
 package cmd
 
 import (
-	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"launchdata/bubble"
 	"launchdata/config"
@@ -13,21 +13,11 @@ import (
 
 func browseCmd() *cobra.Command {
 	cmdBrowse := &cobra.Command{
-		Use:   "browse [flags] year",
-		Short: "",
-		Long:  `TODO`,
-		Args: func(cmd *cobra.Command, args []string) error {
-			if len(args) < 1 {
-				// TODO check the cache location for what years we have
-				// if we have no years, suggest running the
-				// cache command
-				return errors.New("requires a year (1950-2022)")
-			}
-			if _, err := strconv.Atoi(args[0]); err != nil {
-				return fmt.Errorf("%q looks like it's not a year. Try 2021", args[0])
-			}
-			return nil
-		},
+		Use:               "browse [flags] year",
+		Short:             "Browse the launches, rendezvous and EVAs in a cached year",
+		Long:              `Browse the launches in a year that has already been downloaded with the cache command`,
+		Args:              cachedYearArg,
+		ValidArgsFunction: completeCachedYears,
 		Run: func(cmd *cobra.Command, args []string) {
 			year, _ := strconv.Atoi(args[0])
 			config := config.Init(cmd)
@@ -37,3 +27,21 @@ func browseCmd() *cobra.Command {
 
 	return cmdBrowse
 }
+
+// describeYears summarises a sorted list of years as ranges, e.g. "1951-1960, 2022"
+func describeYears(years []int) string {
+	var ranges []string
+	for i := 0; i < len(years); {
+		j := i
+		for j+1 < len(years) && years[j+1] == years[j]+1 {
+			j++
+		}
+		if i == j {
+			ranges = append(ranges, strconv.Itoa(years[i]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", years[i], years[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(ranges, ", ")
+}
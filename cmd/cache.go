@@ -2,9 +2,12 @@ package cmd
 
 import (
 	"fmt"
-	"path"
+	"time"
 
 	"launchdata/config"
+	"launchdata/dataset"
+	"launchdata/history"
+	"launchdata/manifest"
 	"launchdata/parse"
 
 	"github.com/spf13/cobra"
@@ -15,23 +18,70 @@ func cmdCacheAll() *cobra.Command {
 	cmdCacheAll := &cobra.Command{
 		Use:   "all",
 		Short: "Download all historical launch data from wikipedia",
-		Long:  `TODO`,
+		Long:  `Download every year from the first orbital launch attempts up to the current year, writing one file per year`,
 		Run: func(cmd *cobra.Command, args []string) {
 			config := config.Init(cmd)
-			from := 1951
-			to := 2022
+			if outputDir == "" {
+				outputDir = config.DataDir
+			}
+			from := parse.FirstYear
+			to := parse.LatestYear()
 			fmt.Printf("Caching all files from %d to %d\n", from, to)
+			fetcher := parse.NewFetcher(config)
 			for i := from; i <= to; i++ {
-				filename := path.Join(outputDir, fmt.Sprintf("launchdata-%d.json", i))
-				parse.GetAndWrite(config, i, i, filename)
+				filename := dataset.Filename(outputDir, i)
+				result := fetcher.GetAndWrite(i, i, filename)
+				recordFetch(config, filename, result)
 			}
 		},
 	}
-	cmdCacheAll.Flags().StringVar(&outputDir, "output-dir", "./data", "output directory")
+	cmdCacheAll.Flags().StringVar(&outputDir, "output-dir", "", "output directory (default --data-dir)")
 
 	return cmdCacheAll
 }
 
+func cmdCacheVerify() *cobra.Command {
+	cmdCacheVerify := &cobra.Command{
+		Use:   "verify",
+		Short: "Check the cached files against their manifest",
+		Long: `Check that every file listed in the manifest exists, hasn't been modified
+since it was written, and contains the number of launches it was written with.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := config.Init(cmd).DataDir
+			problems, err := manifest.Verify(dir)
+			if err != nil {
+				return err
+			}
+
+			for _, problem := range problems {
+				fmt.Println(problem)
+			}
+			if len(problems) > 0 {
+				return fmt.Errorf("found %d problems in %s", len(problems), dir)
+			}
+
+			fmt.Printf("All files in %s match %s\n", dir, manifest.Filename)
+			return nil
+		},
+	}
+	return cmdCacheVerify
+}
+
+func updateManifest(config config.Config, filename string, result parse.Result) {
+	if err := manifest.Update(config, filename, result); err != nil {
+		fmt.Println(fmt.Errorf("failed to update the manifest for %s: %w", filename, err))
+	}
+}
+
+// recordFetch keeps track of everything we learnt from freshly fetched data
+func recordFetch(config config.Config, filename string, result parse.Result) {
+	updateManifest(config, filename, result)
+
+	if err := history.Update(config, filename, result.Data.OrbitalFlights, time.Now()); err != nil {
+		fmt.Println(fmt.Errorf("failed to update the launch history for %s: %w", filename, err))
+	}
+}
+
 func cacheCmd() *cobra.Command {
 	var year int
 	var startYear int
@@ -41,27 +91,42 @@ func cacheCmd() *cobra.Command {
 	cmdCache := &cobra.Command{
 		Use:   "cache",
 		Short: "Download launch data from wikipedia and cache it locally",
-		Long:  `TODO`,
-		Run: func(cmd *cobra.Command, args []string) {
+		Long: `Download launch data from wikipedia and cache it locally.
+
+Give a single --year, or a range with --start and --end. Either end of the
+range can be left off, so "--start 2015" caches everything from 2015 up to the
+current year.
+
+Each refresh is also recorded in history.json next to the data, and the
+launches it added and outcomes it changed are written to feed.atom.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			config := config.Init(cmd)
-			if cmd.Flags().Changed("startYear") {
-				parse.GetAndWrite(config, startYear, endYear, outputFilename)
+			var result parse.Result
+			if cmd.Flags().Changed("start") || cmd.Flags().Changed("end") {
+				from, to, err := yearRange(startYear, endYear)
+				if err != nil {
+					return err
+				}
+				result = parse.GetAndWrite(config, from, to, outputFilename)
 			} else {
-				parse.GetAndWrite(config, year, year, outputFilename)
+				result = parse.GetAndWrite(config, year, year, outputFilename)
 			}
+			recordFetch(config, outputFilename, result)
+			return nil
 		},
 	}
-	cmdCache.Flags().IntVarP(&startYear, "start", "s", 2021, "Start Year")
-	cmdCache.Flags().IntVarP(&endYear, "end", "e", 2021, "End Year")
-	cmdCache.MarkFlagsRequiredTogether("start", "end")
+	addYearRangeFlags(cmdCache, &startYear, &endYear)
 
-	cmdCache.Flags().IntVarP(&year, "year", "y", 2021, "Specify a single year")
+	cmdCache.Flags().IntVarP(&year, "year", "y", parse.LatestYear(), "Specify a single year")
 	cmdCache.MarkFlagsMutuallyExclusive("year", "start")
+	cmdCache.MarkFlagsMutuallyExclusive("year", "end")
 
 	cmdCache.Flags().StringVarP(&outputFilename, "output", "o", "", "JSON output file")
+	cmdCache.PersistentFlags().String("archive-dir", "", "also save compressed raw responses to this directory, for use with reparse")
 
 	cmdCacheAll := cmdCacheAll()
 	cmdCache.AddCommand(cmdCacheAll)
+	cmdCache.AddCommand(cmdCacheVerify())
 
 	return cmdCache
 }
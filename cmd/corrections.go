@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+
+	"launchdata/config"
+	"launchdata/parse"
+
+	"github.com/spf13/cobra"
+)
+
+func correctionsCmd() *cobra.Command {
+	cmdCorrections := &cobra.Command{
+		Use:   "corrections",
+		Short: "Check or apply the manual corrections to the cached data",
+		Long: `Manual corrections are JSON files in --corrections-dir, each patching one
+launch by its id. They're applied every time launches are parsed by cache or
+reparse, so they survive a refresh, and every launch they touch lists them
+under Corrections. A correction looks like:
+
+  {
+    "Year": 2022,
+    "LaunchId": "falcon-9-block-5/f9-138",
+    "Author": "Jane Doe",
+    "Reason": "Wikipedia has the wrong pad, see the SpaceX press kit",
+    "Date": "2023-02-01",
+    "Set": {"LaunchSite": "Vandenberg SLC-4E", "Payload[NROL-87].Outcome": "Successful"},
+    "Remove": ["Notes", "Payload[Month header]"],
+    "AddPayloads": [{"Payload": "Rideshare", "Orbit": "SSO"}]
+  }`,
+	}
+	cmdCorrections.AddCommand(correctionsCheckCmd())
+	cmdCorrections.AddCommand(correctionsApplyCmd())
+
+	return cmdCorrections
+}
+
+// correctCachedFiles applies the corrections to every cached file, without
+// writing them
+func correctCachedFiles(config config.Config) (cachedFiles, []parse.RocketData, parse.CorrectionReport, error) {
+	patches, err := parse.LoadCorrections(config.CorrectionsDir)
+	if err != nil {
+		return cachedFiles{}, nil, parse.CorrectionReport{}, err
+	}
+
+	cached, launches, err := loadCachedFiles(config)
+	if err != nil {
+		return cached, nil, parse.CorrectionReport{}, err
+	}
+
+	report := parse.ApplyCorrections(patches, cached.byYear(launches))
+	fmt.Printf("%d of %d corrections apply to the cached years\n", len(report.Applied), len(patches))
+	for _, diagnostic := range report.Diagnostics() {
+		fmt.Printf("%d: %s\n", diagnostic.Year, diagnostic.Message)
+	}
+	return cached, launches, report, nil
+}
+
+func correctionsCheckCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "check",
+		Short: "Report corrections that no longer match a launch or can't be applied",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, _, report, err := correctCachedFiles(config.Init(cmd))
+			if err != nil {
+				return err
+			}
+			if problems := len(report.Stale) + len(report.Failed); problems > 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%d corrections need attention", problems)
+			}
+			return nil
+		},
+	}
+}
+
+func correctionsApplyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "apply",
+		Short: "Apply the corrections to the cached files now, without refreshing them",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := config.Init(cmd)
+			cached, launches, _, err := correctCachedFiles(config)
+			if err != nil {
+				return err
+			}
+			return cached.writeLaunches(config, launches)
+		},
+	}
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"launchdata/config"
+	"launchdata/coverage"
+	"launchdata/dataset"
+	"launchdata/parse"
+
+	"github.com/spf13/cobra"
+)
+
+func coverageCmd() *cobra.Command {
+	var threshold float64
+	var referenceFile string
+
+	cmdCoverage := &cobra.Command{
+		Use:   "coverage",
+		Short: "Compare the number of launches parsed each year against the real totals",
+		Long: `Compare the number of orbital launches parsed for each cached year against
+a reference list of how many orbital launch attempts there really were, and
+flag the years where the gap is bigger than --threshold.
+
+The reference totals are built in, or can be given as a CSV file of
+year,count rows with --reference.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := config.Init(cmd)
+
+			reference, err := coverage.Reference()
+			if referenceFile != "" {
+				reference, err = coverage.LoadReference(referenceFile)
+			}
+			if err != nil {
+				return err
+			}
+
+			years, err := dataset.Years(config.DataDir)
+			if err != nil {
+				return err
+			}
+			parsed := coverage.Totals{}
+			for _, year := range years {
+				data, err := parse.LoadLaunchData(dataset.Filename(config.DataDir, year), loadOptions(config))
+				if err != nil {
+					return err
+				}
+				parsed[year] = len(data.OrbitalFlights)
+			}
+
+			report := coverage.Compare(parsed, reference, threshold)
+			flagged := printCoverage(report)
+			if flagged > 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%d years differ from the reference by more than %.0f%%", flagged, threshold*100)
+			}
+			return nil
+		},
+	}
+	cmdCoverage.Flags().Float64Var(&threshold, "threshold", 0.1, "flag years whose gap is more than this fraction of the expected total")
+	cmdCoverage.Flags().StringVar(&referenceFile, "reference", "", "CSV file of expected totals per year (default built in)")
+
+	return cmdCoverage
+}
+
+func printCoverage(report []coverage.Year) int {
+	flagged := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "Year\tParsed\tExpected\tGap\t\t")
+	for _, y := range report {
+		flag := ""
+		if y.Flagged {
+			flag = "!"
+			flagged++
+		}
+		fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%.0f%%\t%s\n", y.Year, y.Parsed, y.Expected, y.Gap, y.GapRatio*100, flag)
+	}
+	w.Flush()
+	return flagged
+}
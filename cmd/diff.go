@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"launchdata/config"
+	"launchdata/dataset"
+	"launchdata/diff"
+
+	"github.com/spf13/cobra"
+)
+
+func diffCmd() *cobra.Command {
+	var format string
+
+	cmdDiff := &cobra.Command{
+		Use:   "diff [flags] old-dir new-dir",
+		Short: "Show what changed between two snapshots of the cached data",
+		Long: `Compare two directories of cached launch data, e.g. before and after a
+refresh, and report the orbital and suborbital launches that were added,
+removed or modified.
+
+Launches are matched by their id, so a launch whose date moved shows up as a
+modified timestamp rather than as a removal and an addition.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options := loadOptions(config.Init(cmd))
+			old, err := dataset.LoadAll(args[0], options)
+			if err != nil {
+				return err
+			}
+			new, err := dataset.LoadAll(args[1], options)
+			if err != nil {
+				return err
+			}
+
+			report := diff.Compare(old, new)
+			return report.Write(os.Stdout, format)
+		},
+	}
+	cmdDiff.Flags().StringVarP(&format, "format", "f", diff.FormatText, fmt.Sprintf("output format (%s)", strings.Join(diff.Formats, ", ")))
+
+	return cmdDiff
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"launchdata/config"
+	"launchdata/dataset"
+	"launchdata/export"
+	"launchdata/parse"
+	"launchdata/sites"
+
+	"github.com/spf13/cobra"
+)
+
+func exportCmd() *cobra.Command {
+	var format string
+	var startYear, endYear int
+	var options export.Options
+	var geoOptions export.GeoOptions
+	var gazetteerFile string
+
+	cmdExport := &cobra.Command{
+		Use:   "export [flags] [output]",
+		Short: "Export the cached launches in other formats",
+		Long: fmt.Sprintf(`Export the cached orbital launches for the years between --start and --end.
+
+For csv and tsv, output is a directory (default the current one) to write
+launches.csv and payloads.csv into. Launches are flattened to one row each,
+and payloads are joined to them by the LaunchId column. CSV is quoted as RFC
+4180 describes, so notes spanning several lines stay in one field.
+
+For sqlite, output is the database file to write (default launchdata.db),
+which is replaced if it exists. Vehicles, sites and organizations get tables
+of their own, referred to by id from launches and payloads, and the
+launches_flat view joins them back together. The column options don't apply.
+
+For ndjson, output is the file to write (default stdout), with each launch as
+a JSON object on a line of its own, e.g. for jq. Years are read a launch at a
+time, so memory use doesn't grow with the range exported.
+
+For ics, output is the calendar file to write (default launchdata.ics), with
+an event for every launch. Launches with an exact time get an hour long
+event, and those with only a day, a month or a quarter get an all day event
+spanning it. Event UIDs are launch ids, so subscribed calendars update
+events when the cache is refreshed rather than adding them again.
+
+For geojson and kml, output is the file to write (default launchsites.geojson
+or launchsites.kml), with a point for every spaceport that has launched,
+carrying its launch counts, success rate, first and last launches and most
+launched vehicles. --launches adds a point for every launch too. Spaceports
+are placed using a built in gazetteer, or the CSV file given by --gazetteer.
+
+Launch columns: %s
+Payload columns: %s`, strings.Join(export.LaunchColumnNames(), ", "), strings.Join(export.PayloadColumnNames(), ", ")),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := config.Init(cmd)
+
+			years, err := cachedYearsInRange(config, startYear, endYear)
+			if err != nil {
+				return err
+			}
+			output := ""
+			if len(args) > 0 {
+				output = args[0]
+			}
+
+			// Streamed, so it doesn't need everything loaded first
+			switch format {
+			case export.FormatNdjson:
+				return exportNdjson(config, years, output)
+			case export.FormatIcs:
+				return exportIcs(config, years, output)
+			case export.FormatSqlite:
+				if output == "" {
+					output = "launchdata.db"
+				}
+				return export.WriteSqlite(config, streamLaunches(config, years), output)
+			}
+
+			data, err := dataset.LoadYears(config.DataDir, years, loadOptions(config))
+			if err != nil {
+				return err
+			}
+
+			switch format {
+			case export.FormatCsv, export.FormatTsv:
+				if output == "" {
+					output = "."
+				}
+				return export.WriteTables(config, data.OrbitalFlights, format, options, output)
+			case export.FormatGeoJson, export.FormatKml:
+				gazetteer, err := sites.Default()
+				if gazetteerFile != "" {
+					gazetteer, err = sites.Load(gazetteerFile)
+				}
+				if err != nil {
+					return err
+				}
+				if output == "" {
+					output = "launchsites." + format
+				}
+				return export.WriteMap(config, gazetteer.Summarise(data.OrbitalFlights), format, geoOptions, output)
+			default:
+				return fmt.Errorf("unknown format %q, expected one of %s", format, strings.Join(export.Formats, ", "))
+			}
+		},
+	}
+	cmdExport.Flags().StringVarP(&format, "format", "f", export.FormatCsv, fmt.Sprintf("one of %s", strings.Join(export.Formats, ", ")))
+	addYearRangeFlags(cmdExport, &startYear, &endYear)
+	cmdExport.Flags().StringSliceVar(&options.LaunchColumns, "columns", nil, "launch columns to write, in order (default all)")
+	cmdExport.Flags().StringSliceVar(&options.PayloadColumns, "payload-columns", nil, "payload columns to write, in order (default all)")
+	cmdExport.Flags().BoolVar(&options.NoHeader, "no-header", false, "leave out the header row")
+	cmdExport.Flags().BoolVar(&geoOptions.Launches, "launches", false, "add a point for every launch to maps")
+	cmdExport.Flags().StringVar(&gazetteerFile, "gazetteer", "", "CSV of launch site locations for maps (default built in)")
+
+	return cmdExport
+}
+
+func exportNdjson(config config.Config, years []int, output string) error {
+	if config.DryRun && output != "" && output != "-" {
+		fmt.Printf("Dry run: would write launches to %s\n", output)
+		return nil
+	}
+
+	out := os.Stdout
+	if output != "" && output != "-" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	w := bufio.NewWriter(out)
+	writer := export.NewNdjsonWriter(w)
+	write := func(year int, launch parse.RocketData) error {
+		return writer.Write(launch)
+	}
+	if err := dataset.Stream(config.DataDir, years, loadOptions(config), write); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if out != os.Stdout {
+		fmt.Printf("Wrote %d launches to %s\n", writer.Count, output)
+		return out.Close()
+	}
+	return nil
+}
+
+func exportIcs(config config.Config, years []int, output string) error {
+	if output == "" {
+		output = "launchdata.ics"
+	}
+
+	calendar := export.NewCalendar(time.Now())
+	add := func(year int, launch parse.RocketData) error {
+		calendar.Add(year, launch)
+		return nil
+	}
+	if err := dataset.Stream(config.DataDir, years, loadOptions(config), add); err != nil {
+		return err
+	}
+	return export.WriteIcs(config, calendar, output)
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"launchdata/config"
+	"launchdata/dataset"
+	"launchdata/manifest"
+	"launchdata/parse"
+
+	"github.com/spf13/cobra"
+)
+
+func migrateCmd() *cobra.Command {
+	cmdMigrate := &cobra.Command{
+		Use:   "migrate",
+		Short: "Upgrade the cached data files to the current schema, in place",
+		Long: fmt.Sprintf(`Upgrade every cached data file in --data-dir to schema version %d, so
+files written by older versions of launchdata keep loading correctly.
+Files that are already up to date aren't touched.`, parse.SchemaVersion),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := config.Init(cmd)
+
+			years, err := dataset.Years(config.DataDir)
+			if err != nil {
+				return err
+			}
+
+			for _, year := range years {
+				if err := migrateFile(config, dataset.Filename(config.DataDir, year)); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	return cmdMigrate
+}
+
+func migrateFile(config config.Config, filename string) error {
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	migrated, from, err := parse.Migrate(contents)
+	if err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+	if from == parse.SchemaVersion {
+		return nil
+	}
+
+	var data parse.AllLaunchData
+	if err := json.Unmarshal(migrated, &data); err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+	parse.AssignIds(data.OrbitalFlights)
+	parse.AssignIds(data.SuborbitalFlights)
+
+	if config.DryRun {
+		fmt.Printf("Dry run: would migrate %s from schema version %d to %d\n", filename, from, parse.SchemaVersion)
+		return nil
+	}
+
+	fmt.Printf("Migrating %s from schema version %d to %d\n", filename, from, parse.SchemaVersion)
+	if err := parse.WriteLaunchDataFile(config, data, filename); err != nil {
+		return err
+	}
+	return manifest.Rehash(config, filename)
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"launchdata/config"
+	"launchdata/gcat"
+
+	"github.com/spf13/cobra"
+)
+
+func reconcileCmd() *cobra.Command {
+	var gcatFile string
+	var enrich bool
+	var asJson bool
+	options := gcat.DefaultOptions
+
+	cmdReconcile := &cobra.Command{
+		Use:   "reconcile --gcat launchlog.tsv",
+		Short: "Compare the cached launches with GCAT's launch log",
+		Long: `Compare the cached orbital launches with a copy of the launch log from
+Jonathan McDowell's GCAT (https://planet4589.org/space/gcat/tsv/launch/launchlog.tsv),
+matching launches by time and vehicle. Reports the launches missing from
+either side, and the matched launches whose times or outcomes disagree.
+
+With --enrich, GCAT's launch tag and COSPAR id are written into the cached
+files for every matched launch. The next cache run overwrites them, so run
+reconcile again after refreshing.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := config.Init(cmd)
+
+			log, err := gcat.Load(gcatFile)
+			if err != nil {
+				return err
+			}
+
+			cached, launches, err := loadCachedFiles(config)
+			if err != nil {
+				return err
+			}
+
+			report := gcat.Reconcile(launches, log, cached.years, time.Now(), options)
+			if asJson {
+				if err := printJson(report); err != nil {
+					return err
+				}
+			} else {
+				printReconcileReport(report)
+			}
+
+			if enrich {
+				gcat.Enrich(launches, report)
+				return cached.writeLaunches(config, launches)
+			}
+			return nil
+		},
+	}
+	cmdReconcile.Flags().StringVar(&gcatFile, "gcat", "", "GCAT launch log in TSV format")
+	cmdReconcile.Flags().BoolVar(&enrich, "enrich", false, "write GCAT's launch tags and COSPAR ids into the cached files")
+	cmdReconcile.Flags().BoolVar(&asJson, "json", false, "print json instead of a report")
+	cmdReconcile.Flags().DurationVar(&options.Tolerance, "tolerance", options.Tolerance, "how far apart matched launch times can be before they disagree")
+	cmdReconcile.MarkFlagRequired("gcat")
+
+	return cmdReconcile
+}
+
+func printReconcileReport(report gcat.Report) {
+	fmt.Printf("Matched %d launches\n", report.Matched)
+
+	printMissing := func(title string, missing []gcat.Missing) {
+		if len(missing) == 0 {
+			return
+		}
+		fmt.Printf("\n%s (%d)\n", title, len(missing))
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		for _, m := range missing {
+			fmt.Fprintf(w, "  %s\t%s\t%s%s\t\n", m.Date, m.Vehicle, m.LaunchId, m.GcatTag)
+		}
+		w.Flush()
+	}
+	printMissing("Missing from GCAT", report.MissingFromGcat)
+	printMissing("Missing from the cached data", report.MissingFromOurs)
+
+	printDisagreements := func(title string, disagreements []gcat.Disagreement) {
+		if len(disagreements) == 0 {
+			return
+		}
+		fmt.Printf("\n%s (%d)\n", title, len(disagreements))
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "  Launch\tGCAT\tOurs\tGCAT's\t")
+		for _, d := range disagreements {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t\n", d.LaunchId, d.GcatTag, d.Ours, d.Gcat)
+		}
+		w.Flush()
+	}
+	printDisagreements("Timestamps that disagree", report.TimestampDisagreements)
+	printDisagreements("Outcomes that disagree", report.OutcomeDisagreements)
+}
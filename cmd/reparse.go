@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"launchdata/archive"
+	"launchdata/config"
+	"launchdata/dataset"
+	"launchdata/parse"
+
+	"github.com/spf13/cobra"
+)
+
+func reparseCmd() *cobra.Command {
+	var archiveDir string
+	var outputDir string
+	var startYear int
+	var endYear int
+	var asOf string
+
+	cmdReparse := &cobra.Command{
+		Use:   "reparse",
+		Short: "Regenerate the cached launch data from archived raw responses",
+		Long: `Regenerate the cached launch data from the raw responses saved by
+"cache --archive-dir", without making any network requests.
+
+Use --as-of to rebuild the dataset as it would have looked using only the
+responses fetched up to that time.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := config.Init(cmd)
+
+			var asOfTime time.Time
+			if asOf != "" {
+				t, err := parseAsOf(asOf)
+				if err != nil {
+					return err
+				}
+				asOfTime = t
+			}
+
+			from, to, err := yearRange(startYear, endYear)
+			if err != nil {
+				return err
+			}
+			if outputDir == "" {
+				outputDir = config.DataDir
+			}
+
+			a := archive.Open(archiveDir)
+			for i := from; i <= to; i++ {
+				filename := dataset.Filename(outputDir, i)
+				result, err := parse.ReparseAndWrite(config, a, asOfTime, i, i, filename)
+				if err != nil {
+					fmt.Printf("Skipping %d: %v\n", i, err)
+					continue
+				}
+				updateManifest(config, filename, result)
+			}
+			return nil
+		},
+	}
+	cmdReparse.Flags().StringVar(&archiveDir, "archive-dir", "./archive", "directory containing archived raw responses")
+	cmdReparse.Flags().StringVar(&outputDir, "output-dir", "", "output directory (default --data-dir)")
+	addYearRangeFlags(cmdReparse, &startYear, &endYear)
+	cmdReparse.Flags().StringVar(&asOf, "as-of", "", "only use responses fetched at or before this time (2006-01-02 or RFC 3339)")
+
+	return cmdReparse
+}
+
+func parseAsOf(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return t, fmt.Errorf("%q is not a date (2006-01-02) or RFC 3339 timestamp", value)
+	}
+
+	// A bare date includes everything fetched during that day
+	return t.Add(24*time.Hour - time.Nanosecond), nil
+}
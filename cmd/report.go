@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"launchdata/config"
+	"launchdata/dataset"
+	"launchdata/parse"
+	"launchdata/report"
+
+	"github.com/spf13/cobra"
+)
+
+func reportCmd() *cobra.Command {
+	var format, templateFile string
+	var printTemplate bool
+
+	cmdReport := &cobra.Command{
+		Use:   "report [flags] year",
+		Short: "Summarise a cached year's launches as a report",
+		Long: `Summarise the orbital launches in a cached year: how many there were and how
+many failed, the top providers, vehicles and launch sites, the failures with
+their notes and the maiden flights, e.g.
+
+  launchdata report 2022 --format md
+
+The report is written from a template, which --template replaces with one of
+your own. Templates use Go's text/template and are given a report.Report;
+--print-template prints the built in one to start from.
+
+Maiden flights are found by looking at the cached years before the one
+reported on, so cache those too for them to be right. A vehicle has flown once
+any configuration of it has, whatever its upper stage or configuration
+number, e.g. "Atlas V 511" after "Atlas V 401".`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if printTemplate {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cachedYearArg(cmd, args)
+		},
+		ValidArgsFunction: completeCachedYears,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if printTemplate {
+				text, err := report.DefaultTemplate(format)
+				if err != nil {
+					return err
+				}
+				fmt.Print(text)
+				return nil
+			}
+
+			var tmpl *template.Template
+			var err error
+			if templateFile != "" {
+				tmpl, err = report.LoadTemplate(templateFile)
+			} else {
+				tmpl, err = report.Template(format)
+			}
+			if err != nil {
+				return err
+			}
+
+			config := config.Init(cmd)
+			year, _ := strconv.Atoi(args[0])
+			cached, err := dataset.Years(config.DataDir)
+			if err != nil {
+				return err
+			}
+			var earlier []int
+			for _, y := range cached {
+				if y < year {
+					earlier = append(earlier, y)
+				}
+			}
+			if missing := year - parse.FirstYear - len(earlier); missing > 0 {
+				fmt.Fprintf(os.Stderr, "%d of the years before %d aren't cached, so some of the maiden flights listed may not be firsts\n", missing, year)
+			}
+
+			flown := report.Flown{}
+			var launches []parse.RocketData
+			collect := func(y int, launch parse.RocketData) error {
+				if y == year {
+					launches = append(launches, launch)
+				} else {
+					flown.Add(launch)
+				}
+				return nil
+			}
+			if err := dataset.Stream(config.DataDir, append(earlier, year), loadOptions(config), collect); err != nil {
+				return err
+			}
+
+			return report.Build(year, launches, flown, time.Now()).Write(os.Stdout, tmpl)
+		},
+	}
+	cmdReport.Flags().StringVarP(&format, "format", "f", report.FormatText, fmt.Sprintf("one of %s", strings.Join(report.Formats, ", ")))
+	cmdReport.Flags().StringVar(&templateFile, "template", "", "write the report with this template instead of the built in one")
+	cmdReport.Flags().BoolVar(&printTemplate, "print-template", false, "print the built in template for --format")
+
+	return cmdReport
+}
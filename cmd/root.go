@@ -1,6 +1,10 @@
 package cmd
 
 import (
+	"launchdata/config"
+	"launchdata/dataset"
+	"launchdata/parse"
+
 	"github.com/sanity-io/litter"
 	"github.com/spf13/cobra"
 )
@@ -15,9 +19,30 @@ func Root() *cobra.Command {
 		Short: "Launchdata 🚀\nA tool to download and examine rocket launch data from Wikipedia",
 	}
 	rootCmd.PersistentFlags().Bool("dry-run", false, "Don't actually take any actions")
+	rootCmd.PersistentFlags().String("data-dir", dataset.DefaultDir, "directory containing the cached launch data")
+	rootCmd.PersistentFlags().Bool("strict", false, "refuse to load cached files containing unknown fields")
+	rootCmd.PersistentFlags().String("corrections-dir", "", "directory of manual corrections to apply to parsed launches (default corrections next to --data-dir)")
 
 	rootCmd.AddCommand(cacheCmd())
 	rootCmd.AddCommand(browseCmd())
+	rootCmd.AddCommand(reparseCmd())
+	rootCmd.AddCommand(diffCmd())
+	rootCmd.AddCommand(slipsCmd())
+	rootCmd.AddCommand(migrateCmd())
+	rootCmd.AddCommand(validateCmd())
+	rootCmd.AddCommand(coverageCmd())
+	rootCmd.AddCommand(boostersCmd())
+	rootCmd.AddCommand(reconcileCmd())
+	rootCmd.AddCommand(satcatCmd())
+	rootCmd.AddCommand(correctionsCmd())
+	rootCmd.AddCommand(exportCmd())
+	rootCmd.AddCommand(sqlCmd())
+	rootCmd.AddCommand(siteCmd())
+	rootCmd.AddCommand(reportCmd())
 
 	return rootCmd
 }
+
+func loadOptions(config config.Config) parse.LoadOptions {
+	return parse.LoadOptions{Strict: config.Strict}
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"launchdata/config"
+	"launchdata/satcat"
+
+	"github.com/spf13/cobra"
+)
+
+func satcatCmd() *cobra.Command {
+	var catalogFile string
+	var enrich bool
+	var asJson bool
+
+	cmdSatcat := &cobra.Command{
+		Use:   "satcat --file satcat.csv",
+		Short: "Attach NORAD catalog numbers to payloads from a SATCAT export",
+		Long: `Match the payloads in the cached files against a satellite catalog export in
+CeleStrak's CSV format (https://celestrak.org/pub/satcat.csv), and report how
+many matched.
+
+Payloads are matched by launch date and name, and by the launch's COSPAR id
+when reconcile --enrich has filled it in. Payloads that could be more than
+one object, like batches of satellites, are reported and left alone.
+
+With --enrich, the NORAD id, international designator, object type, status
+and decay date of each match are written into the cached files. The next
+cache run overwrites them, so run satcat again after refreshing.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := config.Init(cmd)
+
+			objects, err := satcat.Load(catalogFile)
+			if err != nil {
+				return err
+			}
+
+			cached, launches, err := loadCachedFiles(config)
+			if err != nil {
+				return err
+			}
+
+			report := satcat.Join(launches, objects)
+			if asJson {
+				if err := printJson(report); err != nil {
+					return err
+				}
+			} else {
+				printSatcatReport(report)
+			}
+
+			if enrich && report.Matched > 0 {
+				return cached.writeLaunches(config, launches)
+			}
+			return nil
+		},
+	}
+	cmdSatcat.Flags().StringVar(&catalogFile, "file", "", "SATCAT export in CSV format")
+	cmdSatcat.Flags().BoolVar(&enrich, "enrich", false, "write the catalog details of matched payloads into the cached files")
+	cmdSatcat.Flags().BoolVar(&asJson, "json", false, "print json instead of a report")
+	cmdSatcat.MarkFlagRequired("file")
+
+	return cmdSatcat
+}
+
+func printSatcatReport(report satcat.Report) {
+	fmt.Printf("Matched %d payloads, %d unmatched, %d ambiguous\n", report.Matched, report.Unmatched, len(report.Ambiguous))
+	for _, ambiguity := range report.Ambiguous {
+		fmt.Printf("\n%s %q could be any of:\n  %s\n", ambiguity.LaunchId, ambiguity.Payload, strings.Join(ambiguity.Candidates, "\n  "))
+	}
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"launchdata/config"
+	"launchdata/dataset"
+	"launchdata/parse"
+	"launchdata/site"
+
+	"github.com/spf13/cobra"
+)
+
+func siteCmd() *cobra.Command {
+	var out string
+	var startYear, endYear int
+
+	cmdSite := &cobra.Command{
+		Use:   "site --out public/",
+		Short: "Generate a static website of the cached launches",
+		Long: `Generate a static website from the cached orbital launches for the years
+between --start and --end: an index of years, a page for each year listing
+its launches and their payloads, and a page for each vehicle, launch site and
+provider with its history and statistics.
+
+The pages are plain HTML and CSS, with no JavaScript, so the directory can be
+served by any web server or opened straight from disk. Existing files in it
+are overwritten, but files for pages that no longer exist are left alone.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := config.Init(cmd)
+
+			years, err := cachedYearsInRange(config, startYear, endYear)
+			if err != nil {
+				return err
+			}
+
+			var launches []site.Launch
+			collect := func(year int, launch parse.RocketData) error {
+				launches = append(launches, site.Launch{Year: year, Launch: launch})
+				return nil
+			}
+			if err := dataset.Stream(config.DataDir, years, loadOptions(config), collect); err != nil {
+				return err
+			}
+
+			pages, err := site.Build(launches, time.Now()).Write(config, out)
+			if err != nil {
+				return err
+			}
+			if config.DryRun {
+				fmt.Printf("Dry run: would write %d files to %s\n", pages, out)
+			} else {
+				fmt.Printf("Wrote %d files to %s\n", pages, out)
+			}
+			return nil
+		},
+	}
+	cmdSite.Flags().StringVarP(&out, "out", "o", "public", "directory to write the site to")
+	addYearRangeFlags(cmdSite, &startYear, &endYear)
+
+	return cmdSite
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"launchdata/config"
+	"launchdata/history"
+
+	"github.com/spf13/cobra"
+)
+
+func slipsCmd() *cobra.Command {
+	var historyFile string
+	var by string
+
+	cmdSlips := &cobra.Command{
+		Use:   "slips",
+		Short: "Report how much upcoming launches slipped, by provider and vehicle",
+		Long: `Report how much the schedule of upcoming launches moved across cache
+refreshes. Every cache run records the scheduled time of each upcoming launch
+in history.json, next to the cached data, so this report gets more useful the
+more often the cache is refreshed.
+
+A scrub is any time a launch moved later, including to a vaguer time like
+"TBD". Days slipped is the distance between the first and last precise times
+we saw.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if historyFile == "" {
+				historyFile = history.Path(config.Init(cmd).DataDir)
+			}
+
+			h, err := history.Load(historyFile)
+			if err != nil {
+				return err
+			}
+			if len(h.Launches) == 0 {
+				return fmt.Errorf("no launch history in %s, it's recorded each time the cache command runs", historyFile)
+			}
+
+			slips := h.Slips()
+			switch by {
+			case "provider":
+				printSlipStats("Provider", history.ByProvider(slips))
+			case "vehicle":
+				printSlipStats("Vehicle", history.ByVehicle(slips))
+			case "":
+				printSlipStats("Provider", history.ByProvider(slips))
+				fmt.Println()
+				printSlipStats("Vehicle", history.ByVehicle(slips))
+			default:
+				return fmt.Errorf("can't group by %q, expected provider or vehicle", by)
+			}
+			return nil
+		},
+	}
+	cmdSlips.Flags().StringVar(&historyFile, "history", "", "history file (default history.json in --data-dir)")
+	cmdSlips.Flags().StringVar(&by, "by", "", "only group by provider or vehicle")
+
+	return cmdSlips
+}
+
+func printSlipStats(name string, stats []history.GroupStats) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\tLaunches\tScrubs\tMean days\tMedian days\tMax days\t\n", name)
+	for _, s := range stats {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.1f\t%.1f\t%.1f\t\n",
+			s.Name, s.Launches, s.Scrubs, s.MeanDaysSlipped, s.MedianDaysSlipped, s.MaxDaysSlipped)
+	}
+	w.Flush()
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"launchdata/config"
+	"launchdata/query"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func sqlCmd() *cobra.Command {
+	var format string
+	var startYear, endYear int
+
+	cmdSql := &cobra.Command{
+		Use:   "sql [flags] [query]",
+		Short: "Query the cached launches with SQL",
+		Long: `Load the cached orbital launches for the years between --start and --end
+into an in-memory database and run a query against it, e.g.
+
+  launchdata sql "SELECT provider, count(*) FROM launches_flat WHERE year = 2021 GROUP BY provider ORDER BY 2 DESC"
+
+The tables are the same as "export --format sqlite" writes. Without a query,
+statements are read from stdin until they end with ";". In this mode
+.tables lists the tables, .schema shows how they're defined, .format
+switches the output format and .quit leaves.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := config.Init(cmd)
+			if err := query.CheckFormat(format); err != nil {
+				return err
+			}
+
+			years, err := cachedYearsInRange(config, startYear, endYear)
+			if err != nil {
+				return err
+			}
+			db, err := query.Open(streamLaunches(config, years))
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			if len(args) == 1 {
+				result, err := db.Query(args[0])
+				if err != nil {
+					return err
+				}
+				return result.Write(os.Stdout, format)
+			}
+			return runSqlRepl(db, os.Stdin, format)
+		},
+	}
+	cmdSql.Flags().StringVarP(&format, "format", "f", query.FormatTable, fmt.Sprintf("one of %s", strings.Join(query.Formats, ", ")))
+	addYearRangeFlags(cmdSql, &startYear, &endYear)
+
+	return cmdSql
+}
+
+// runSqlRepl reads statements from in and prints their results, until in
+// runs out or .quit
+func runSqlRepl(db *query.Database, in *os.File, format string) error {
+	interactive := term.IsTerminal(int(in.Fd()))
+	prompt := func(continuing bool) {
+		if !interactive {
+			return
+		}
+		if continuing {
+			fmt.Print("   ...> ")
+		} else {
+			fmt.Print("sql> ")
+		}
+	}
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var statement strings.Builder
+	for prompt(false); scanner.Scan(); prompt(statement.Len() > 0) {
+		line := strings.TrimSpace(scanner.Text())
+
+		if statement.Len() == 0 && strings.HasPrefix(line, ".") {
+			fields := strings.Fields(line)
+			switch fields[0] {
+			case ".quit", ".exit":
+				return nil
+			case ".tables":
+				tables, err := db.Tables()
+				if err != nil {
+					fmt.Println(err)
+					continue
+				}
+				fmt.Println(strings.Join(tables, "  "))
+			case ".schema":
+				schema, err := db.Schema()
+				if err != nil {
+					fmt.Println(err)
+					continue
+				}
+				fmt.Println(schema)
+			case ".format":
+				if len(fields) != 2 {
+					fmt.Printf("usage: .format %s\n", strings.Join(query.Formats, "|"))
+					continue
+				}
+				if err := query.CheckFormat(fields[1]); err != nil {
+					fmt.Println(err)
+					continue
+				}
+				format = fields[1]
+			default:
+				fmt.Printf("unknown command %s, expected .tables, .schema, .format or .quit\n", fields[0])
+			}
+			continue
+		}
+
+		if line == "" {
+			continue
+		}
+		statement.WriteString(line)
+		statement.WriteString("\n")
+		if !strings.HasSuffix(line, ";") {
+			continue
+		}
+
+		result, err := db.Query(statement.String())
+		statement.Reset()
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		if err := result.Write(os.Stdout, format); err != nil {
+			fmt.Println(err)
+		}
+	}
+	if interactive {
+		fmt.Println()
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"launchdata/config"
+	"launchdata/validate"
+
+	"github.com/spf13/cobra"
+)
+
+func validateCmd() *cobra.Command {
+	var rules []string
+	var severities map[string]string
+	var format string
+	var failOn string
+	var listRules bool
+
+	cmdValidate := &cobra.Command{
+		Use:   "validate [flags] [data-dir]",
+		Short: "Check the cached data for likely parsing mistakes",
+		Long: `Run a set of rules over the cached data, looking for things like launches
+with no payloads, timestamps in the wrong year or notes mistaken for payloads.
+
+Exits with an error if anything at or above --fail-on is found, so it can be
+used to stop bad data being committed. Use --list-rules to see the rules and
+their default severities.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if listRules {
+				printRules()
+				return nil
+			}
+
+			config := config.Init(cmd)
+			dir := config.DataDir
+			if len(args) > 0 {
+				dir = args[0]
+			}
+
+			validateConfig := validate.Config{Rules: rules, Severities: map[string]validate.Severity{}}
+			for rule, level := range severities {
+				severity, err := validate.ParseSeverity(level)
+				if err != nil {
+					return err
+				}
+				validateConfig.Severities[rule] = severity
+			}
+			threshold, err := validate.ParseSeverity(failOn)
+			if err != nil {
+				return err
+			}
+
+			files, err := validate.LoadDir(dir, loadOptions(config))
+			if err != nil {
+				return err
+			}
+			findings, err := validate.Run(validateConfig, files, time.Now())
+			if err != nil {
+				return err
+			}
+			if err := validate.Write(os.Stdout, format, findings); err != nil {
+				return err
+			}
+
+			failures := 0
+			for _, finding := range findings {
+				if threshold != validate.SeverityOff && finding.Severity.AtLeast(threshold) {
+					failures++
+				}
+			}
+			if failures > 0 {
+				// The findings have already been printed
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%d findings at or above %s", failures, threshold)
+			}
+			return nil
+		},
+	}
+	cmdValidate.Flags().StringSliceVar(&rules, "rules", nil, "only run these rules (default all)")
+	cmdValidate.Flags().StringToStringVar(&severities, "severity", nil, "override rule severities, e.g. no-payload=error,unknown-outcome=off")
+	cmdValidate.Flags().StringVarP(&format, "format", "f", validate.FormatText, fmt.Sprintf("output format (%s)", strings.Join(validate.Formats, ", ")))
+	cmdValidate.Flags().StringVar(&failOn, "fail-on", string(validate.SeverityError), "fail if there are findings at or above this severity (error, warning, note or off)")
+	cmdValidate.Flags().BoolVar(&listRules, "list-rules", false, "list the available rules")
+
+	return cmdValidate
+}
+
+func printRules() {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "Rule\tSeverity\tDescription")
+	for _, rule := range validate.Rules {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", rule.Name, rule.Severity, rule.Description)
+	}
+	w.Flush()
+}
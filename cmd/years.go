@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"launchdata/config"
+	"launchdata/dataset"
+	"launchdata/export"
+	"launchdata/manifest"
+	"launchdata/parse"
+
+	"github.com/spf13/cobra"
+)
+
+// addYearRangeFlags adds --start and --end flags. Either can be left off to
+// leave that end of the range open.
+func addYearRangeFlags(cmd *cobra.Command, startYear *int, endYear *int) {
+	cmd.Flags().IntVarP(startYear, "start", "s", 0, fmt.Sprintf("Start Year (default %d)", parse.FirstYear))
+	cmd.Flags().IntVarP(endYear, "end", "e", 0, "End Year (default the current year)")
+}
+
+// yearRange closes any open ends of the range given to addYearRangeFlags
+func yearRange(startYear int, endYear int) (int, int, error) {
+	if startYear == 0 {
+		startYear = parse.FirstYear
+	}
+	if endYear == 0 {
+		endYear = parse.LatestYear()
+	}
+	if startYear > endYear {
+		return startYear, endYear, fmt.Errorf("start year %d is after end year %d", startYear, endYear)
+	}
+	return startYear, endYear, nil
+}
+
+// cachedYearArg checks that a command's only argument is a cached year
+func cachedYearArg(cmd *cobra.Command, args []string) error {
+	dir := config.Init(cmd).DataDir
+	years, err := dataset.Years(dir)
+	if err != nil || len(years) == 0 {
+		return fmt.Errorf("no cached years found in %s, try running \"launchdata cache all --output-dir %s\" first", dir, dir)
+	}
+
+	if len(args) < 1 {
+		return fmt.Errorf("requires a year, cached years are %s", describeYears(years))
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("accepts a single year, received %d arguments", len(args))
+	}
+	year, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("%q looks like it's not a year. Try %d", args[0], years[len(years)-1])
+	}
+	for _, y := range years {
+		if y == year {
+			return nil
+		}
+	}
+	return fmt.Errorf("%d hasn't been cached, cached years are %s", year, describeYears(years))
+}
+
+// completeCachedYears offers the years that have been cached as completions
+// for a single year argument
+func completeCachedYears(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	years, err := dataset.Years(config.Init(cmd).DataDir)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	for _, year := range years {
+		completions = append(completions, strconv.Itoa(year))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// streamLaunches reads the orbital launches cached for years when a database
+// is filled with them
+func streamLaunches(config config.Config, years []int) export.Launches {
+	return func(add func(year int, launch parse.RocketData) error) error {
+		return dataset.Stream(config.DataDir, years, loadOptions(config), add)
+	}
+}
+
+// cachedFiles are all the cached years, loaded so that the launches in them
+// can be updated and written back
+type cachedFiles struct {
+	years []int
+	files map[int]parse.AllLaunchData
+}
+
+// loadCachedFiles reads every cached year, returning their orbital launches
+// in one slice, in the order writeLaunches expects them back
+func loadCachedFiles(config config.Config) (cachedFiles, []parse.RocketData, error) {
+	cached := cachedFiles{files: map[int]parse.AllLaunchData{}}
+	years, err := dataset.Years(config.DataDir)
+	if err != nil {
+		return cached, nil, err
+	}
+	cached.years = years
+
+	var launches []parse.RocketData
+	for _, year := range years {
+		data, err := parse.LoadLaunchData(dataset.Filename(config.DataDir, year), loadOptions(config))
+		if err != nil {
+			return cached, nil, err
+		}
+		cached.files[year] = data
+		launches = append(launches, data.OrbitalFlights...)
+	}
+	return cached, launches, nil
+}
+
+// byYear splits launches, as returned by loadCachedFiles, back into the years
+// they came from, along with each year's suborbital flights. The slices share
+// their launches with launches and the cached files, so changes to them are
+// written back by writeLaunches.
+func (c cachedFiles) byYear(launches []parse.RocketData) map[int][][]parse.RocketData {
+	byYear := map[int][][]parse.RocketData{}
+	for _, year := range c.years {
+		data := c.files[year]
+		n := len(data.OrbitalFlights)
+		byYear[year] = [][]parse.RocketData{launches[:n], data.SuborbitalFlights}
+		launches = launches[n:]
+	}
+	return byYear
+}
+
+// writeLaunches writes launches, as returned by loadCachedFiles, back into
+// the files they came from
+func (c cachedFiles) writeLaunches(config config.Config, launches []parse.RocketData) error {
+	for _, year := range c.years {
+		data := c.files[year]
+		n := len(data.OrbitalFlights)
+		data.OrbitalFlights, launches = launches[:n], launches[n:]
+
+		filename := dataset.Filename(config.DataDir, year)
+		if config.DryRun {
+			fmt.Printf("Dry run: would update %s\n", filename)
+			continue
+		}
+		if err := parse.WriteLaunchDataFile(config, data, filename); err != nil {
+			return err
+		}
+		if err := manifest.Rehash(config, filename); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cachedYearsInRange is the cached years within the range given to
+// addYearRangeFlags
+func cachedYearsInRange(config config.Config, startYear int, endYear int) ([]int, error) {
+	startYear, endYear, err := yearRange(startYear, endYear)
+	if err != nil {
+		return nil, err
+	}
+
+	cached, err := dataset.Years(config.DataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var years []int
+	for _, year := range cached {
+		if year >= startYear && year <= endYear {
+			years = append(years, year)
+		}
+	}
+	if len(years) == 0 {
+		return nil, fmt.Errorf("no years between %d and %d are cached in %s", startYear, endYear, config.DataDir)
+	}
+	return years, nil
+}
@@ -1,9 +1,27 @@
 package config
 
-import "github.com/spf13/cobra"
+import (
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
 
 type Config struct {
 	DryRun bool
+
+	// ArchiveDir is where raw responses are kept, empty if we aren't keeping them
+	ArchiveDir string
+
+	// DataDir is where the parsed launch data is cached
+	DataDir string
+
+	// Strict rejects cached files containing fields we don't know about
+	Strict bool
+
+	// CorrectionsDir holds the manual corrections applied to freshly parsed
+	// launches, empty to apply none. It defaults to "corrections" next to
+	// DataDir, so it moves with the data.
+	CorrectionsDir string
 }
 
 func Init(cmd *cobra.Command) Config {
@@ -12,7 +30,36 @@ func Init(cmd *cobra.Command) Config {
 		panic(err)
 	}
 
+	// Only some commands take an archive directory
+	var archiveDir string
+	if flag := cmd.Flags().Lookup("archive-dir"); flag != nil {
+		archiveDir = flag.Value.String()
+	}
+
+	dataDir, err := cmd.Flags().GetString("data-dir")
+	if err != nil {
+		panic(err)
+	}
+
+	strict, err := cmd.Flags().GetBool("strict")
+	if err != nil {
+		panic(err)
+	}
+
+	correctionsDir, err := cmd.Flags().GetString("corrections-dir")
+	if err != nil {
+		panic(err)
+	}
+	if !cmd.Flags().Changed("corrections-dir") {
+		correctionsDir = filepath.Join(filepath.Dir(filepath.Clean(dataDir)), "corrections")
+	}
+
 	return Config{
-		DryRun: dryRun,
+		DryRun:     dryRun,
+		ArchiveDir: archiveDir,
+		DataDir:    dataDir,
+		Strict:     strict,
+
+		CorrectionsDir: correctionsDir,
 	}
 }
@@ -0,0 +1,121 @@
+// Package coverage compares how many orbital launches we parsed each year
+// against how many there really were, to catch years the parser silently
+// gets wrong.
+package coverage
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed reference.csv
+var embeddedReference string
+
+// Totals maps a year to its number of orbital launch attempts
+type Totals map[int]int
+
+// Reference returns the embedded totals from reference.csv
+func Reference() (Totals, error) {
+	return readTotals(strings.NewReader(embeddedReference))
+}
+
+// LoadReference reads totals from a CSV file with the same layout as
+// reference.csv: a header, then year,count rows. Lines starting with # are
+// ignored.
+func LoadReference(filename string) (Totals, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	totals, err := readTotals(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return totals, nil
+}
+
+func readTotals(r io.Reader) (Totals, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 2
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no totals")
+	}
+
+	totals := Totals{}
+	// The first record is the header
+	for _, record := range records[1:] {
+		year, err := strconv.Atoi(strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("bad year %q", record[0])
+		}
+		count, err := strconv.Atoi(strings.TrimSpace(record[1]))
+		if err != nil {
+			return nil, fmt.Errorf("bad count %q for %d", record[1], year)
+		}
+		totals[year] = count
+	}
+	return totals, nil
+}
+
+type Year struct {
+	Year     int
+	Parsed   int
+	Expected int
+
+	// Gap is how many launches we're missing, negative if we parsed extra
+	Gap int
+
+	// GapRatio is the size of the gap relative to the expected total
+	GapRatio float64
+
+	Flagged bool
+}
+
+// Compare lines up the parsed and expected totals for every year in parsed,
+// flagging years where the gap is more than threshold (e.g. 0.1 for 10%) of
+// the expected total. Years we have no reference for are skipped.
+func Compare(parsed Totals, expected Totals, threshold float64) []Year {
+	var years []Year
+	for year, count := range parsed {
+		want, ok := expected[year]
+		if !ok {
+			continue
+		}
+
+		y := Year{Year: year, Parsed: count, Expected: want, Gap: want - count}
+		switch {
+		case want > 0:
+			y.GapRatio = float64(abs(y.Gap)) / float64(want)
+			y.Flagged = y.GapRatio > threshold
+		case count > 0:
+			// Anything parsed for a year with no launches is wrong
+			y.GapRatio = 1
+			y.Flagged = true
+		}
+		years = append(years, y)
+	}
+
+	sort.Slice(years, func(i, j int) bool { return years[i].Year < years[j].Year })
+	return years
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,44 @@
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddedReference(t *testing.T) {
+	totals, err := Reference()
+	require.NoError(t, err)
+	assert.Equal(t, 0, totals[1956])
+	assert.Greater(t, totals[2022], 0)
+}
+
+func TestLoadReference(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "totals.csv")
+	require.NoError(t, os.WriteFile(filename, []byte("# comment\nyear,count\n2021,146\n2022, 186\n"), 0o644))
+
+	got, err := LoadReference(filename)
+	require.NoError(t, err)
+	assert.Equal(t, Totals{2021: 146, 2022: 186}, got)
+
+	require.NoError(t, os.WriteFile(filename, []byte("year,count\n2021,lots\n"), 0o644))
+	_, err = LoadReference(filename)
+	assert.Error(t, err)
+}
+
+func TestCompare(t *testing.T) {
+	parsed := Totals{1956: 5, 1958: 0, 2021: 140, 2022: 198, 2030: 3}
+	expected := Totals{1956: 0, 1958: 28, 2021: 146, 2022: 186}
+
+	got := Compare(parsed, expected, 0.05)
+
+	assert.Equal(t, []Year{
+		{Year: 1956, Parsed: 5, Expected: 0, Gap: -5, GapRatio: 1, Flagged: true},
+		{Year: 1958, Parsed: 0, Expected: 28, Gap: 28, GapRatio: 1, Flagged: true},
+		{Year: 2021, Parsed: 140, Expected: 146, Gap: 6, GapRatio: 6.0 / 146},
+		{Year: 2022, Parsed: 198, Expected: 186, Gap: -12, GapRatio: 12.0 / 186, Flagged: true},
+	}, got)
+}
@@ -0,0 +1,114 @@
+// Package dataset knows how cached launch data is laid out on disk
+package dataset
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"launchdata/parse"
+)
+
+const DefaultDir = "./data"
+
+var filenameRegex = regexp.MustCompile(`^launchdata-(\d{4})\.json$`)
+
+// Filename is where the data for year is cached in dir
+func Filename(dir string, year int) string {
+	return filepath.Join(dir, fmt.Sprintf("launchdata-%d.json", year))
+}
+
+// Years lists the years that have been cached in dir, in order
+func Years(dir string) ([]int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var years []int
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := filenameRegex.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		year, _ := strconv.Atoi(match[1])
+		years = append(years, year)
+	}
+	sort.Ints(years)
+
+	return years, nil
+}
+
+// LaunchId makes id, which is only unique within the file cached for year,
+// unique across the whole dataset, e.g. "1955/r-1/29-january". It doesn't
+// depend on which other years are loaded, so it's as stable as id.
+func LaunchId(year int, id string) string {
+	return fmt.Sprintf("%d/%s", year, id)
+}
+
+func qualifyIds(year int, launches []parse.RocketData) {
+	for i := range launches {
+		launches[i].Id = LaunchId(year, launches[i].Id)
+	}
+}
+
+// LoadYears reads the data cached in dir for each of years and combines it.
+// Launch ids are qualified with their year, see LaunchId.
+func LoadYears(dir string, years []int, options parse.LoadOptions) (parse.AllLaunchData, error) {
+	var all parse.AllLaunchData
+	for _, year := range years {
+		data, err := parse.LoadLaunchData(Filename(dir, year), options)
+		if err != nil {
+			return all, err
+		}
+		qualifyIds(year, data.OrbitalFlights)
+		qualifyIds(year, data.SuborbitalFlights)
+		all.OrbitalFlights = append(all.OrbitalFlights, data.OrbitalFlights...)
+		all.SuborbitalFlights = append(all.SuborbitalFlights, data.SuborbitalFlights...)
+	}
+	return all, nil
+}
+
+// LoadAll reads every year cached in dir
+func LoadAll(dir string, options parse.LoadOptions) (parse.AllLaunchData, error) {
+	years, err := Years(dir)
+	if err != nil {
+		return parse.AllLaunchData{}, err
+	}
+	return LoadYears(dir, years, options)
+}
+
+// Stream reads the launches cached in dir for each of years one at a time,
+// calling each for every orbital launch along with the year it's cached
+// under. Launch ids are qualified with their year, as LoadYears does.
+func Stream(dir string, years []int, options parse.LoadOptions, each func(year int, launch parse.RocketData) error) error {
+	for _, year := range years {
+		launches, err := parse.OpenLaunches(Filename(dir, year), options)
+		if err != nil {
+			return err
+		}
+
+		for launches.Next() {
+			if launches.Suborbital() {
+				continue
+			}
+			launch := launches.Launch()
+			launch.Id = LaunchId(year, launch.Id)
+			if err := each(year, launch); err != nil {
+				launches.Close()
+				return err
+			}
+		}
+		launches.Close()
+		if err := launches.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package dataset
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"launchdata/parse"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYears(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"launchdata-2022.json", "launchdata-1957.json", "manifest.json", "launchdata-20222.json", "notes.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o644))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "launchdata-2000.json"), 0o755))
+
+	got, err := Years(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1957, 2022}, got)
+}
+
+func TestFilename(t *testing.T) {
+	assert.Equal(t, filepath.Join("data", "launchdata-1969.json"), Filename("data", 1969))
+}
+
+func TestStreamMatchesLoadYears(t *testing.T) {
+	dir := t.TempDir()
+	write := func(year int, contents string) {
+		require.NoError(t, os.WriteFile(Filename(dir, year), []byte(contents), 0o644))
+	}
+	write(2021, `{"SchemaVersion": 1, "OrbitalFlights": [{"Id": "electron/f1", "Rocket": "Electron", "FlightNumber": "F1"}], "SuborbitalFlights": [{"Id": "new-shepard/ns-1"}]}`)
+	write(2022, `{"SchemaVersion": 1, "OrbitalFlights": [{"Id": "electron/f1", "Rocket": "Electron", "FlightNumber": "F1"}, {"Id": "electron/f2", "Rocket": "Electron"}]}`)
+
+	var streamed []string
+	require.NoError(t, Stream(dir, []int{2021, 2022}, parse.LoadOptions{}, func(year int, launch parse.RocketData) error {
+		streamed = append(streamed, launch.Id)
+		return nil
+	}))
+
+	loaded, err := LoadYears(dir, []int{2021, 2022}, parse.LoadOptions{})
+	require.NoError(t, err)
+	var want []string
+	for _, launch := range loaded.OrbitalFlights {
+		want = append(want, launch.Id)
+	}
+	assert.Equal(t, want, streamed)
+	assert.Equal(t, []string{"2021/electron/f1", "2022/electron/f1", "2022/electron/f2"}, streamed)
+}
+
+func TestIdsAreUniqueAcrossYears(t *testing.T) {
+	dir := t.TempDir()
+	// Files written before launches had ids, whose launches share a base id
+	launch := `{"Rocket": "R-1", "Timestamp": {"TimestampClean": "29 January"}}`
+	for _, year := range []int{1951, 1955} {
+		require.NoError(t, os.WriteFile(Filename(dir, year), []byte(`{"OrbitalFlights": [`+launch+`]}`), 0o644))
+	}
+
+	loaded, err := LoadYears(dir, []int{1951, 1955}, parse.LoadOptions{})
+	require.NoError(t, err)
+	require.Len(t, loaded.OrbitalFlights, 2)
+	assert.Equal(t, "1951/r-1/29-january", loaded.OrbitalFlights[0].Id)
+	assert.Equal(t, "1955/r-1/29-january", loaded.OrbitalFlights[1].Id)
+
+	// The id doesn't depend on which other years are loaded
+	only, err := LoadYears(dir, []int{1955}, parse.LoadOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "1955/r-1/29-january", only.OrbitalFlights[0].Id)
+}
@@ -0,0 +1,162 @@
+// Package diff compares two snapshots of the launch data, matching launches
+// by their Id so that launches which moved or changed show up as
+// modifications rather than an add and a remove.
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"launchdata/parse"
+)
+
+type Change struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// Lists of launches that are compared separately
+const (
+	ListOrbital    = "orbital"
+	ListSuborbital = "suborbital"
+)
+
+// Launch is a launch that was added or removed, and the list it's in
+type Launch struct {
+	List string
+	parse.RocketData
+}
+
+type Modified struct {
+	Id      string
+	List    string
+	Old     parse.RocketData
+	New     parse.RocketData
+	Changes []Change
+}
+
+type Report struct {
+	Added    []Launch
+	Removed  []Launch
+	Modified []Modified
+}
+
+func (r Report) Empty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Modified) == 0
+}
+
+// Compare reports the orbital and suborbital launches that were added,
+// removed or modified between old and new. Launches must already have Ids.
+func Compare(old parse.AllLaunchData, new parse.AllLaunchData) Report {
+	var report Report
+	report.compareList(ListOrbital, old.OrbitalFlights, new.OrbitalFlights)
+	report.compareList(ListSuborbital, old.SuborbitalFlights, new.SuborbitalFlights)
+	return report
+}
+
+func (report *Report) compareList(list string, old []parse.RocketData, new []parse.RocketData) {
+	oldById := map[string]parse.RocketData{}
+	for _, launch := range old {
+		oldById[launch.Id] = launch
+	}
+	newById := map[string]parse.RocketData{}
+	for _, launch := range new {
+		newById[launch.Id] = launch
+	}
+
+	for _, launch := range new {
+		previous, ok := oldById[launch.Id]
+		if !ok {
+			report.Added = append(report.Added, Launch{list, launch})
+			continue
+		}
+		if changes := compareLaunch(previous, launch); len(changes) > 0 {
+			report.Modified = append(report.Modified, Modified{
+				Id:      launch.Id,
+				List:    list,
+				Old:     previous,
+				New:     launch,
+				Changes: changes,
+			})
+		}
+	}
+
+	for _, launch := range old {
+		if _, ok := newById[launch.Id]; !ok {
+			report.Removed = append(report.Removed, Launch{list, launch})
+		}
+	}
+}
+
+func compareField(changes []Change, field string, old string, new string) []Change {
+	if old != new {
+		changes = append(changes, Change{Field: field, Old: old, New: new})
+	}
+	return changes
+}
+
+func compareLaunch(old parse.RocketData, new parse.RocketData) []Change {
+	var changes []Change
+	changes = compareField(changes, "Timestamp", old.Timestamp.TimeString(), new.Timestamp.TimeString())
+	changes = compareField(changes, "Rocket", old.Rocket, new.Rocket)
+	changes = compareField(changes, "FlightNumber", old.FlightNumber, new.FlightNumber)
+	changes = compareField(changes, "LaunchSite", old.LaunchSite, new.LaunchSite)
+	changes = compareField(changes, "LaunchServiceProvider", old.LaunchServiceProvider, new.LaunchServiceProvider)
+	changes = compareField(changes, "Notes", old.Notes, new.Notes)
+	return append(changes, comparePayloads(old.Payload, new.Payload)...)
+}
+
+// comparePayloads matches payloads by name. Names can repeat within a launch
+// (e.g. several "Starlink" entries), so repeats are matched up in order.
+func comparePayloads(old []parse.PayloadData, new []parse.PayloadData) []Change {
+	oldByName := map[string][]parse.PayloadData{}
+	for _, payload := range old {
+		oldByName[payload.Payload] = append(oldByName[payload.Payload], payload)
+	}
+
+	var changes []Change
+	for _, payload := range new {
+		matches := oldByName[payload.Payload]
+		if len(matches) == 0 {
+			changes = append(changes, Change{Field: payloadField(payload, ""), New: describePayload(payload)})
+			continue
+		}
+		previous := matches[0]
+		oldByName[payload.Payload] = matches[1:]
+
+		changes = compareField(changes, payloadField(payload, "Operator"), previous.Operator, payload.Operator)
+		changes = compareField(changes, payloadField(payload, "Orbit"), previous.Orbit, payload.Orbit)
+		changes = compareField(changes, payloadField(payload, "Function"), previous.Function, payload.Function)
+		changes = compareField(changes, payloadField(payload, "Decay"), previous.Decay, payload.Decay)
+		changes = compareField(changes, payloadField(payload, "Outcome"), previous.Outcome, payload.Outcome)
+		changes = compareField(changes, payloadField(payload, "Cubesat"), fmt.Sprint(previous.Cubesat), fmt.Sprint(payload.Cubesat))
+	}
+
+	// Whatever wasn't matched up has been removed
+	var names []string
+	for name := range oldByName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, payload := range oldByName[name] {
+			changes = append(changes, Change{Field: payloadField(payload, ""), Old: describePayload(payload)})
+		}
+	}
+
+	return changes
+}
+
+func payloadField(payload parse.PayloadData, field string) string {
+	name := strings.TrimSpace(payload.Payload)
+	if field == "" {
+		return fmt.Sprintf("Payload[%s]", name)
+	}
+	return fmt.Sprintf("Payload[%s].%s", name, field)
+}
+
+func describePayload(payload parse.PayloadData) string {
+	return fmt.Sprintf("%s, %s, %s", payload.Operator, payload.Orbit, payload.Outcome)
+}
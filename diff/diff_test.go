@@ -0,0 +1,94 @@
+package diff
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"launchdata/parse"
+)
+
+func launch(id string, day int, payloads ...parse.PayloadData) parse.RocketData {
+	return parse.RocketData{
+		Id:     id,
+		Rocket: "Falcon 9 Block 5",
+		Timestamp: parse.TimeData{
+			Timestamp: time.Date(2022, 1, day, 12, 0, 0, 0, time.UTC),
+			ParsedOk:  true,
+		},
+		Payload: payloads,
+	}
+}
+
+func TestCompare(t *testing.T) {
+	starlink := parse.PayloadData{Payload: "Starlink × 49", Operator: "SpaceX", Outcome: "Operational"}
+	rideshare := parse.PayloadData{Payload: "Rideshare", Operator: "Various", Outcome: "Operational"}
+	failed := starlink
+	failed.Outcome = "Spacecraft failure"
+
+	old := []parse.RocketData{
+		launch("unchanged", 1, starlink),
+		launch("slipped", 2, starlink),
+		launch("removed", 3),
+		launch("payloads", 4, starlink, rideshare),
+	}
+	new := []parse.RocketData{
+		launch("unchanged", 1, starlink),
+		launch("slipped", 5, starlink),
+		launch("payloads", 4, failed),
+		launch("added", 6),
+	}
+
+	report := Compare(parse.AllLaunchData{OrbitalFlights: old}, parse.AllLaunchData{OrbitalFlights: new})
+
+	assert.Equal(t, []Launch{{ListOrbital, new[3]}}, report.Added)
+	assert.Equal(t, []Launch{{ListOrbital, old[2]}}, report.Removed)
+	require.Len(t, report.Modified, 2)
+
+	assert.Equal(t, "slipped", report.Modified[0].Id)
+	assert.Equal(t, ListOrbital, report.Modified[0].List)
+	assert.Equal(t, []Change{{Field: "Timestamp", Old: "2022-01-02 12:00 (UTC)", New: "2022-01-05 12:00 (UTC)"}}, report.Modified[0].Changes)
+
+	assert.Equal(t, "payloads", report.Modified[1].Id)
+	assert.Equal(t, []Change{
+		{Field: "Payload[Starlink × 49].Outcome", Old: "Operational", New: "Spacecraft failure"},
+		{Field: "Payload[Rideshare]", Old: "Various, , Operational"},
+	}, report.Modified[1].Changes)
+}
+
+func TestCompareSuborbitalFlights(t *testing.T) {
+	old := parse.AllLaunchData{
+		OrbitalFlights:    []parse.RocketData{launch("a", 1)},
+		SuborbitalFlights: []parse.RocketData{launch("ns-1", 1), launch("ns-2", 2)},
+	}
+	new := parse.AllLaunchData{
+		OrbitalFlights:    []parse.RocketData{launch("a", 1)},
+		SuborbitalFlights: []parse.RocketData{launch("ns-1", 3), launch("ns-3", 4)},
+	}
+
+	report := Compare(old, new)
+	assert.Equal(t, []Launch{{ListSuborbital, new.SuborbitalFlights[1]}}, report.Added)
+	assert.Equal(t, []Launch{{ListSuborbital, old.SuborbitalFlights[1]}}, report.Removed)
+	require.Len(t, report.Modified, 1)
+	assert.Equal(t, "ns-1", report.Modified[0].Id)
+	assert.Equal(t, ListSuborbital, report.Modified[0].List)
+}
+
+func TestWriteFormats(t *testing.T) {
+	report := Compare(
+		parse.AllLaunchData{OrbitalFlights: []parse.RocketData{launch("a", 1)}},
+		parse.AllLaunchData{OrbitalFlights: []parse.RocketData{launch("a", 2)}, SuborbitalFlights: []parse.RocketData{launch("b", 3)}},
+	)
+
+	for _, format := range Formats {
+		var buf bytes.Buffer
+		require.NoError(t, report.Write(&buf, format))
+		assert.Contains(t, buf.String(), "2022-01-03", format)
+		assert.Contains(t, buf.String(), "suborbital", format)
+	}
+
+	assert.Error(t, report.Write(&bytes.Buffer{}, "xml"))
+}
@@ -0,0 +1,129 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"launchdata/jsonio"
+	"launchdata/parse"
+)
+
+const (
+	FormatText     = "text"
+	FormatJson     = "json"
+	FormatMarkdown = "markdown"
+)
+
+var Formats = []string{FormatText, FormatJson, FormatMarkdown}
+
+func (r Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatText:
+		return r.WriteText(w)
+	case FormatJson:
+		return r.WriteJson(w)
+	case FormatMarkdown:
+		return r.WriteMarkdown(w)
+	}
+	return fmt.Errorf("unknown format %q, expected one of %s", format, strings.Join(Formats, ", "))
+}
+
+func describeLaunch(list string, launch parse.RocketData) string {
+	return fmt.Sprintf("%s %s (%s, %s)", launch.Timestamp.DateString(), strings.TrimSpace(launch.Rocket), launch.Id, list)
+}
+
+func describeChange(change Change) string {
+	switch {
+	case change.Old == "":
+		return fmt.Sprintf("%s: added %q", change.Field, change.New)
+	case change.New == "":
+		return fmt.Sprintf("%s: removed %q", change.Field, change.Old)
+	}
+	return fmt.Sprintf("%s: %q -> %q", change.Field, change.Old, change.New)
+}
+
+func (r Report) summary() string {
+	return fmt.Sprintf("%d added, %d removed, %d modified", len(r.Added), len(r.Removed), len(r.Modified))
+}
+
+func (r Report) WriteText(w io.Writer) error {
+	fmt.Fprintln(w, r.summary())
+
+	for _, launch := range r.Added {
+		fmt.Fprintf(w, "+ %s\n", describeLaunch(launch.List, launch.RocketData))
+	}
+	for _, launch := range r.Removed {
+		fmt.Fprintf(w, "- %s\n", describeLaunch(launch.List, launch.RocketData))
+	}
+	for _, modified := range r.Modified {
+		fmt.Fprintf(w, "~ %s\n", describeLaunch(modified.List, modified.New))
+		for _, change := range modified.Changes {
+			fmt.Fprintf(w, "    %s\n", describeChange(change))
+		}
+	}
+
+	return nil
+}
+
+func (r Report) WriteJson(w io.Writer) error {
+	formatted, err := jsonio.FormattedJson(r)
+	if err != nil {
+		return err
+	}
+	_, err = formatted.WriteTo(w)
+	return err
+}
+
+// markdownEscape stops table cells from being split up or reformatted
+func markdownEscape(input string) string {
+	input = strings.ReplaceAll(input, "|", `\|`)
+	return strings.ReplaceAll(input, "\n", " ")
+}
+
+func (r Report) WriteMarkdown(w io.Writer) error {
+	fmt.Fprintf(w, "## Dataset changes\n\n%s\n", r.summary())
+
+	writeLaunchTable := func(title string, launches []Launch) {
+		if len(launches) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "\n### %s\n\n", title)
+		fmt.Fprintln(w, "| Id | List | Date | Rocket | Site | Payloads |")
+		fmt.Fprintln(w, "| --- | --- | --- | --- | --- | --- |")
+		for _, launch := range launches {
+			var payloads []string
+			for _, payload := range launch.Payload {
+				payloads = append(payloads, payload.Payload)
+			}
+			fmt.Fprintf(w, "| `%s` | %s | %s | %s | %s | %s |\n",
+				launch.Id,
+				launch.List,
+				launch.Timestamp.DateString(),
+				markdownEscape(strings.TrimSpace(launch.Rocket)),
+				markdownEscape(strings.TrimSpace(launch.LaunchSite)),
+				markdownEscape(strings.Join(payloads, ", ")))
+		}
+	}
+
+	writeLaunchTable("Added", r.Added)
+	writeLaunchTable("Removed", r.Removed)
+
+	if len(r.Modified) > 0 {
+		fmt.Fprint(w, "\n### Modified\n\n")
+		fmt.Fprintln(w, "| Id | List | Field | Old | New |")
+		fmt.Fprintln(w, "| --- | --- | --- | --- | --- |")
+		for _, modified := range r.Modified {
+			for _, change := range modified.Changes {
+				fmt.Fprintf(w, "| `%s` | %s | %s | %s | %s |\n",
+					modified.Id,
+					modified.List,
+					markdownEscape(change.Field),
+					markdownEscape(change.Old),
+					markdownEscape(change.New))
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package export
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"launchdata/parse"
+)
+
+// LaunchColumn is a column of the flattened launches
+type LaunchColumn struct {
+	Name  string
+	Value func(launch parse.RocketData) string
+}
+
+// PayloadColumn is a column of the payloads, which are joined to their
+// launch by LaunchId
+type PayloadColumn struct {
+	Name  string
+	Value func(launch parse.RocketData, index int, payload parse.PayloadData) string
+}
+
+func clean(s string) string {
+	return strings.TrimSpace(s)
+}
+
+func formatInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// Timestamp is RFC 3339 when we could parse it, and whatever wikipedia said
+// otherwise, e.g. "Q3"
+func Timestamp(t parse.TimeData) string {
+	if !t.ParsedOk {
+		return clean(t.TimestampClean)
+	}
+	return t.Timestamp.Format(time.RFC3339)
+}
+
+func boosterSerials(launch parse.RocketData) string {
+	var serials []string
+	for _, booster := range launch.Boosters {
+		serials = append(serials, booster.Serial)
+	}
+	return strings.Join(serials, " ")
+}
+
+// LaunchColumns are all the launch columns, in their default order
+var LaunchColumns = []LaunchColumn{
+	{"Id", func(l parse.RocketData) string { return l.Id }},
+	{"Date", func(l parse.RocketData) string { return clean(l.Timestamp.DateString()) }},
+	{"Timestamp", func(l parse.RocketData) string { return Timestamp(l.Timestamp) }},
+	{"TimestampParsed", func(l parse.RocketData) string { return strconv.FormatBool(l.Timestamp.ParsedOk) }},
+	{"Tbd", func(l parse.RocketData) string { return strconv.FormatBool(l.Timestamp.Tbd) }},
+	{"Rocket", func(l parse.RocketData) string { return clean(l.Rocket) }},
+	{"FlightNumber", func(l parse.RocketData) string { return clean(l.FlightNumber) }},
+	{"LaunchSite", func(l parse.RocketData) string { return clean(l.LaunchSite) }},
+	{"LaunchServiceProvider", func(l parse.RocketData) string { return clean(l.LaunchServiceProvider) }},
+	{"Outcome", func(l parse.RocketData) string { return string(l.Outcome()) }},
+	{"Payloads", func(l parse.RocketData) string { return strconv.Itoa(len(l.Payload)) }},
+	{"Boosters", boosterSerials},
+	{"PayloadMass", func(l parse.RocketData) string { return clean(l.PayloadMass) }},
+	{"Customer", func(l parse.RocketData) string { return clean(l.Customer) }},
+	{"CosparId", func(l parse.RocketData) string { return l.CosparId }},
+	{"GcatLaunchTag", func(l parse.RocketData) string { return l.GcatLaunchTag }},
+	{"Notes", func(l parse.RocketData) string { return clean(l.Notes) }},
+}
+
+// PayloadColumns are all the payload columns, in their default order
+var PayloadColumns = []PayloadColumn{
+	{"LaunchId", func(l parse.RocketData, i int, p parse.PayloadData) string { return l.Id }},
+	{"Index", func(l parse.RocketData, i int, p parse.PayloadData) string { return strconv.Itoa(i) }},
+	{"Payload", func(l parse.RocketData, i int, p parse.PayloadData) string { return clean(p.Payload) }},
+	{"Operator", func(l parse.RocketData, i int, p parse.PayloadData) string { return clean(p.Operator) }},
+	{"Orbit", func(l parse.RocketData, i int, p parse.PayloadData) string { return clean(p.Orbit) }},
+	{"Function", func(l parse.RocketData, i int, p parse.PayloadData) string { return clean(p.Function) }},
+	{"Decay", func(l parse.RocketData, i int, p parse.PayloadData) string { return clean(p.Decay) }},
+	{"Outcome", func(l parse.RocketData, i int, p parse.PayloadData) string { return clean(p.Outcome) }},
+	{"Cubesat", func(l parse.RocketData, i int, p parse.PayloadData) string { return strconv.FormatBool(p.Cubesat) }},
+	{"NoradId", func(l parse.RocketData, i int, p parse.PayloadData) string { return formatInt(p.NoradId) }},
+	{"IntlDesignator", func(l parse.RocketData, i int, p parse.PayloadData) string { return p.IntlDesignator }},
+	{"ObjectType", func(l parse.RocketData, i int, p parse.PayloadData) string { return p.ObjectType }},
+	{"Status", func(l parse.RocketData, i int, p parse.PayloadData) string { return p.Status }},
+	{"DecayDate", func(l parse.RocketData, i int, p parse.PayloadData) string { return p.DecayDate }},
+}
+
+func columnNames[C any](columns []C, name func(C) string) []string {
+	var names []string
+	for _, column := range columns {
+		names = append(names, name(column))
+	}
+	return names
+}
+
+// selectColumns picks the named columns, in the order they're named, or all
+// of them if names is empty
+func selectColumns[C any](columns []C, name func(C) string, names []string) ([]C, error) {
+	if len(names) == 0 {
+		return columns, nil
+	}
+
+	var selected []C
+	for _, wanted := range names {
+		found := false
+		for _, column := range columns {
+			if strings.EqualFold(name(column), strings.TrimSpace(wanted)) {
+				selected = append(selected, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown column %q, expected one of %s", wanted, strings.Join(columnNames(columns, name), ", "))
+		}
+	}
+	return selected, nil
+}
+
+func launchColumnName(c LaunchColumn) string   { return c.Name }
+func payloadColumnName(c PayloadColumn) string { return c.Name }
+
+func LaunchColumnNames() []string  { return columnNames(LaunchColumns, launchColumnName) }
+func PayloadColumnNames() []string { return columnNames(PayloadColumns, payloadColumnName) }
@@ -0,0 +1,137 @@
+// Package export writes the cached launch data in formats other tools can
+// read directly, like spreadsheets
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"launchdata/config"
+	"launchdata/parse"
+)
+
+const (
+	FormatCsv = "csv"
+	FormatTsv = "tsv"
+)
+
+var Formats = []string{FormatCsv, FormatTsv, FormatSqlite, FormatNdjson, FormatIcs, FormatGeoJson, FormatKml}
+
+type Options struct {
+	// LaunchColumns and PayloadColumns pick the columns to write, by name, all
+	// of them if empty
+	LaunchColumns  []string
+	PayloadColumns []string
+
+	NoHeader bool
+}
+
+// Table is a flattened set of rows ready to be written out
+type Table struct {
+	Header []string
+	Rows   [][]string
+}
+
+func LaunchTable(launches []parse.RocketData, names []string) (Table, error) {
+	columns, err := selectColumns(LaunchColumns, launchColumnName, names)
+	if err != nil {
+		return Table{}, err
+	}
+
+	table := Table{Header: columnNames(columns, launchColumnName)}
+	for _, launch := range launches {
+		var row []string
+		for _, column := range columns {
+			row = append(row, column.Value(launch))
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, nil
+}
+
+func PayloadTable(launches []parse.RocketData, names []string) (Table, error) {
+	columns, err := selectColumns(PayloadColumns, payloadColumnName, names)
+	if err != nil {
+		return Table{}, err
+	}
+
+	table := Table{Header: columnNames(columns, payloadColumnName)}
+	for _, launch := range launches {
+		for i, payload := range launch.Payload {
+			var row []string
+			for _, column := range columns {
+				row = append(row, column.Value(launch, i, payload))
+			}
+			table.Rows = append(table.Rows, row)
+		}
+	}
+	return table, nil
+}
+
+// Write writes table as CSV, quoted as RFC 4180 describes so that multi-line
+// notes survive, or as TSV
+func (t Table) Write(w io.Writer, format string, header bool) error {
+	writer := csv.NewWriter(w)
+	switch format {
+	case FormatCsv:
+		writer.UseCRLF = true
+	case FormatTsv:
+		writer.Comma = '\t'
+	default:
+		return fmt.Errorf("unknown format %q for a table, expected %s or %s", format, FormatCsv, FormatTsv)
+	}
+
+	if header {
+		if err := writer.Write(t.Header); err != nil {
+			return err
+		}
+	}
+	if err := writer.WriteAll(t.Rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+func writeTableFile(config config.Config, table Table, format string, header bool, filename string) error {
+	if config.DryRun {
+		fmt.Printf("Dry run: would write %d rows to %s\n", len(table.Rows), filename)
+		return nil
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := table.Write(f, format, header); err != nil {
+		f.Close()
+		return err
+	}
+	fmt.Printf("Wrote %d rows to %s\n", len(table.Rows), filename)
+	return f.Close()
+}
+
+// WriteTables writes launches.csv and payloads.csv, or .tsv, into dir
+func WriteTables(config config.Config, launches []parse.RocketData, format string, options Options, dir string) error {
+	launchTable, err := LaunchTable(launches, options.LaunchColumns)
+	if err != nil {
+		return err
+	}
+	payloadTable, err := PayloadTable(launches, options.PayloadColumns)
+	if err != nil {
+		return err
+	}
+
+	if !config.DryRun {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	if err := writeTableFile(config, launchTable, format, !options.NoHeader, filepath.Join(dir, "launches."+format)); err != nil {
+		return err
+	}
+	return writeTableFile(config, payloadTable, format, !options.NoHeader, filepath.Join(dir, "payloads."+format))
+}
@@ -0,0 +1,382 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"launchdata/config"
+	"launchdata/dataset"
+	"launchdata/parse"
+	"launchdata/sites"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLaunches() []parse.RocketData {
+	return []parse.RocketData{
+		{
+			Id:         "2022-01-06-falcon-9",
+			Timestamp:  parse.TimeData{TimestampClean: "6 January 21:49", Timestamp: time.Date(2022, 1, 6, 21, 49, 0, 0, time.UTC), ParsedOk: true},
+			Rocket:     "Falcon 9 Block 5",
+			LaunchSite: "Cape Canaveral SLC-40",
+			Notes:      "Starlink Group 4-5\n\"Booster\" landed, B1062",
+			Payload: []parse.PayloadData{
+				{Payload: "Starlink Group 4-5", Operator: "SpaceX", Orbit: "Low Earth", Outcome: "Successful"},
+			},
+		},
+		{
+			Id:        "2022-q3-long-march",
+			Timestamp: parse.TimeData{TimestampClean: "Q3"},
+			Rocket:    "Long March 2D",
+			Payload: []parse.PayloadData{
+				{Payload: "Yaogan 1"},
+				{Payload: "Yaogan 2", NoradId: 52000},
+			},
+		},
+	}
+}
+
+func TestLaunchTableSelectsColumns(t *testing.T) {
+	table, err := LaunchTable(testLaunches(), []string{"id", "Timestamp", "rocket"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Id", "Timestamp", "Rocket"}, table.Header)
+	assert.Equal(t, [][]string{
+		{"2022-01-06-falcon-9", "2022-01-06T21:49:00Z", "Falcon 9 Block 5"},
+		{"2022-q3-long-march", "Q3", "Long March 2D"},
+	}, table.Rows)
+
+	_, err = LaunchTable(testLaunches(), []string{"Id", "Colour"})
+	assert.ErrorContains(t, err, `unknown column "Colour"`)
+}
+
+func TestPayloadTableJoinsByLaunchId(t *testing.T) {
+	table, err := PayloadTable(testLaunches(), []string{"LaunchId", "Index", "Payload", "NoradId"})
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{
+		{"2022-01-06-falcon-9", "0", "Starlink Group 4-5", ""},
+		{"2022-q3-long-march", "0", "Yaogan 1", ""},
+		{"2022-q3-long-march", "1", "Yaogan 2", "52000"},
+	}, table.Rows)
+}
+
+func TestWriteQuotesMultilineNotes(t *testing.T) {
+	table, err := LaunchTable(testLaunches()[:1], []string{"Id", "Notes"})
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, table.Write(&b, FormatCsv, true))
+	assert.Equal(t, "Id,Notes\r\n2022-01-06-falcon-9,\"Starlink Group 4-5\r\n\"\"Booster\"\" landed, B1062\"\r\n", b.String())
+
+	// Line breaks in quoted fields become CRLF too, and read back as they were
+	records, err := csv.NewReader(&b).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, testLaunches()[0].Notes, records[1][1])
+}
+
+func TestWriteWithoutHeader(t *testing.T) {
+	table, err := LaunchTable(testLaunches(), []string{"Id", "Rocket"})
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, table.Write(&b, FormatTsv, false))
+	assert.Equal(t, "2022-01-06-falcon-9\tFalcon 9 Block 5\n2022-q3-long-march\tLong March 2D\n", b.String())
+
+	assert.ErrorContains(t, table.Write(&b, FormatSqlite, true), `unknown format "sqlite" for a table`)
+}
+
+func TestWriteTables(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+	require.NoError(t, WriteTables(config.Config{}, testLaunches(), FormatCsv, Options{}, dir))
+
+	launches, err := os.ReadFile(filepath.Join(dir, "launches.csv"))
+	require.NoError(t, err)
+	records, err := csv.NewReader(bytes.NewReader(launches)).ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, records, 3)
+	assert.Equal(t, LaunchColumnNames(), records[0])
+
+	payloads, err := os.ReadFile(filepath.Join(dir, "payloads.csv"))
+	require.NoError(t, err)
+	records, err = csv.NewReader(bytes.NewReader(payloads)).ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, records, 4)
+	assert.Equal(t, PayloadColumnNames(), records[0])
+}
+
+func TestCreateDatabase(t *testing.T) {
+	db, err := OpenDatabase(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	launches := testLaunches()
+	launches[1].LaunchServiceProvider = "CASC"
+	launches[1].Payload[0].Operator = "CASC"
+	require.NoError(t, CreateDatabase(db, LaunchesIn(2022, launches)))
+
+	var organizations int
+	require.NoError(t, db.QueryRow("SELECT count(*) FROM organizations").Scan(&organizations))
+	assert.Equal(t, 2, organizations, "SpaceX and CASC, each once")
+
+	rows, err := db.Query("SELECT id, year, vehicle, site, provider, payloads FROM launches_flat ORDER BY id")
+	require.NoError(t, err)
+	defer rows.Close()
+	type flat struct {
+		Id       string
+		Year     int
+		Vehicle  string
+		Site     sql.NullString
+		Provider sql.NullString
+		Payloads int
+	}
+	var got []flat
+	for rows.Next() {
+		var f flat
+		require.NoError(t, rows.Scan(&f.Id, &f.Year, &f.Vehicle, &f.Site, &f.Provider, &f.Payloads))
+		got = append(got, f)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []flat{
+		{"2022-01-06-falcon-9", 2022, "Falcon 9 Block 5", sql.NullString{String: "Cape Canaveral SLC-40", Valid: true}, sql.NullString{}, 1},
+		// The year comes from the file, even when the timestamp couldn't be
+		// parsed
+		{"2022-q3-long-march", 2022, "Long March 2D", sql.NullString{}, sql.NullString{String: "CASC", Valid: true}, 2},
+	}, got)
+
+	var noradId int
+	require.NoError(t, db.QueryRow("SELECT norad_id FROM payloads WHERE name = 'Yaogan 2'").Scan(&noradId))
+	assert.Equal(t, 52000, noradId)
+
+	_, err = db.Exec("INSERT INTO payloads (launch_id, idx, name, cubesat) VALUES ('no-such-launch', 0, 'x', 0)")
+	assert.Error(t, err, "foreign keys should be enforced")
+}
+
+func TestWriteSqliteReplacesExistingFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "launchdata.db")
+	require.NoError(t, WriteSqlite(config.Config{}, LaunchesIn(2022, testLaunches()), filename))
+	require.NoError(t, WriteSqlite(config.Config{}, LaunchesIn(2022, testLaunches()[:1]), filename))
+
+	db, err := OpenDatabase(filename)
+	require.NoError(t, err)
+	defer db.Close()
+	var launches int
+	require.NoError(t, db.QueryRow("SELECT count(*) FROM launches").Scan(&launches))
+	assert.Equal(t, 1, launches)
+
+	leftovers, err := filepath.Glob(filepath.Join(filepath.Dir(filename), "*.tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, leftovers)
+}
+
+func TestNdjsonWriter(t *testing.T) {
+	var b bytes.Buffer
+	writer := NewNdjsonWriter(&b)
+	for _, launch := range testLaunches() {
+		require.NoError(t, writer.Write(launch))
+	}
+	assert.Equal(t, 2, writer.Count)
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	var launch parse.RocketData
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &launch))
+	assert.Equal(t, testLaunches()[0].Notes, launch.Notes)
+}
+
+func TestLaunchWindow(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	launch := time.Date(2022, 1, 6, 21, 49, 0, 0, time.UTC)
+
+	tests := []struct {
+		time parse.TimeData
+		want window
+		ok   bool
+	}{
+		{parse.TimeData{TimestampClean: "6 January21:49", Timestamp: launch, ParsedOk: true}, window{launch, launch.Add(time.Hour), false}, true},
+		{parse.TimeData{TimestampClean: "6 January", Timestamp: day(2022, 1, 6), ParsedOk: true}, window{day(2022, 1, 6), day(2022, 1, 7), true}, true},
+		{parse.TimeData{TimestampClean: "March"}, window{day(2022, 3, 1), day(2022, 4, 1), true}, true},
+		{parse.TimeData{TimestampClean: "Late December"}, window{day(2022, 12, 21), day(2023, 1, 1), true}, true},
+		{parse.TimeData{TimestampClean: "Q3"}, window{day(2022, 7, 1), day(2022, 10, 1), true}, true},
+		{parse.TimeData{TimestampClean: "H2 2023"}, window{day(2023, 7, 1), day(2024, 1, 1), true}, true},
+		{parse.TimeData{TimestampClean: "Mid 2024"}, window{day(2024, 5, 1), day(2024, 9, 1), true}, true},
+		{parse.TimeData{TimestampClean: "2024", Tbd: true}, window{}, false},
+		{parse.TimeData{TimestampClean: ""}, window{}, false},
+	}
+
+	for _, test := range tests {
+		got, ok := launchWindow(test.time, 2022)
+		assert.Equal(t, test.ok, ok, test.time.TimestampClean)
+		assert.Equal(t, test.want, got, test.time.TimestampClean)
+	}
+}
+
+func TestCalendar(t *testing.T) {
+	calendar := NewCalendar(time.Date(2022, 2, 1, 12, 0, 0, 0, time.UTC))
+	launches := testLaunches()
+	launches[0].Notes = "Carried 49 Starlink satellites; the booster, B1062, landed on a drone ship in the Atlantic"
+	assert.True(t, calendar.Add(2022, launches[0]))
+	assert.True(t, calendar.Add(2022, launches[1]))
+	assert.False(t, calendar.Add(2022, parse.RocketData{Rocket: "\n\nFebruary", Timestamp: parse.TimeData{TimestampRaw: "\n\nFebruary", TimestampClean: "\n\nFebruary"}}))
+	assert.False(t, calendar.Add(2022, parse.RocketData{Id: "tbd", Rocket: "Vega", Timestamp: parse.TimeData{TimestampClean: "2022", Tbd: true}}))
+	assert.Equal(t, 1, calendar.Skipped)
+
+	var b bytes.Buffer
+	require.NoError(t, calendar.Write(&b))
+	assert.Equal(t, "BEGIN:VCALENDAR\r\n"+
+		"VERSION:2.0\r\n"+
+		"PRODID:-//launchdata//launchdata//EN\r\n"+
+		"CALSCALE:GREGORIAN\r\n"+
+		"X-WR-CALNAME:Orbital launches\r\n"+
+		"BEGIN:VEVENT\r\n"+
+		"UID:2022/2022-01-06-falcon-9@launchdata\r\n"+
+		"DTSTAMP:20220201T120000Z\r\n"+
+		"DTSTART:20220106T214900Z\r\n"+
+		"DTEND:20220106T224900Z\r\n"+
+		"SUMMARY:Falcon 9 Block 5 | Starlink Group 4-5\r\n"+
+		"LOCATION:Cape Canaveral SLC-40\r\n"+
+		"DESCRIPTION:Outcome: success\\n\\nPayloads:\\n- Starlink Group 4-5 (SpaceX\\, L\r\n"+
+		" ow Earth\\, Successful)\\n\\nCarried 49 Starlink satellites\\; the booster\\, B\r\n"+
+		" 1062\\, landed on a drone ship in the Atlantic\r\n"+
+		"END:VEVENT\r\n"+
+		"BEGIN:VEVENT\r\n"+
+		"UID:2022/2022-q3-long-march@launchdata\r\n"+
+		"DTSTAMP:20220201T120000Z\r\n"+
+		"DTSTART;VALUE=DATE:20220701\r\n"+
+		"DTEND;VALUE=DATE:20221001\r\n"+
+		"SUMMARY:Long March 2D | Yaogan 1 and 1 more\r\n"+
+		"DESCRIPTION:Outcome: unknown\\n\\nPayloads:\\n- Yaogan 1\\n- Yaogan 2\r\n"+
+		"END:VEVENT\r\n"+
+		"END:VCALENDAR\r\n", b.String())
+}
+
+func TestCalendarUidsDontDependOnTheYearsExported(t *testing.T) {
+	dir := t.TempDir()
+	// Files written before launches had ids, whose launches share a base id
+	launch := `{"Rocket": "R-1", "Timestamp": {"TimestampClean": "29 January"}}`
+	for _, year := range []int{1951, 1955} {
+		require.NoError(t, os.WriteFile(dataset.Filename(dir, year), []byte(`{"OrbitalFlights": [`+launch+`]}`), 0o644))
+	}
+
+	uids := func(years ...int) []string {
+		calendar := NewCalendar(time.Now())
+		require.NoError(t, dataset.Stream(dir, years, parse.LoadOptions{}, func(year int, launch parse.RocketData) error {
+			calendar.Add(year, launch)
+			return nil
+		}))
+		var uids []string
+		for _, event := range calendar.events {
+			uids = append(uids, event.uid)
+		}
+		return uids
+	}
+
+	assert.Equal(t, []string{"1951/r-1/29-january@launchdata", "1955/r-1/29-january@launchdata"}, uids(1951, 1955))
+	assert.Equal(t, []string{"1955/r-1/29-january@launchdata"}, uids(1955))
+}
+
+func TestWriteLineFoldsWithoutSplittingCharacters(t *testing.T) {
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+	writeLine(w, "SUMMARY:"+strings.Repeat("é", 40))
+	require.NoError(t, w.Flush())
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n ")
+	require.Len(t, lines, 2)
+	assert.LessOrEqual(t, len(lines[0]), 75)
+	assert.True(t, utf8.ValidString(lines[0]))
+	assert.True(t, utf8.ValidString(lines[1]))
+	assert.Equal(t, "SUMMARY:"+strings.Repeat("é", 40), lines[0]+lines[1])
+}
+
+func testSummary() sites.Summary {
+	site := sites.Site{Name: "Kennedy Space Center", Country: "United States", Latitude: 28.573, Longitude: -80.649}
+	launch := testLaunches()[0]
+	return sites.Summary{
+		Sites: []sites.Stats{{
+			Site:        site,
+			Launches:    2,
+			Successes:   2,
+			SuccessRate: 1,
+			FirstLaunch: "2022-01-06",
+			LastLaunch:  "2022-01-19",
+			TopVehicles: []sites.VehicleCount{{Vehicle: "Falcon 9 Block 5", Launches: 2}},
+		}},
+		Launches: []sites.LocatedLaunch{{Launch: launch, Site: site}},
+	}
+}
+
+func TestWriteGeoJson(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, WriteGeoJson(&b, testSummary(), GeoOptions{Launches: true}))
+
+	var collection struct {
+		Type     string
+		Features []struct {
+			Geometry struct {
+				Type        string
+				Coordinates []float64
+			}
+			Properties map[string]interface{}
+		}
+	}
+	require.NoError(t, json.Unmarshal(b.Bytes(), &collection))
+	assert.Equal(t, "FeatureCollection", collection.Type)
+	require.Len(t, collection.Features, 2)
+
+	site := collection.Features[0]
+	assert.Equal(t, "Point", site.Geometry.Type)
+	assert.Equal(t, []float64{-80.649, 28.573}, site.Geometry.Coordinates, "longitude comes first")
+	assert.Equal(t, "sites", site.Properties["layer"])
+	assert.Equal(t, "Kennedy Space Center", site.Properties["name"])
+	assert.Equal(t, 2.0, site.Properties["launches"])
+	assert.Equal(t, 1.0, site.Properties["successRate"])
+
+	assert.Equal(t, "launches", collection.Features[1].Properties["layer"])
+	assert.Equal(t, "2022-01-06-falcon-9", collection.Features[1].Properties["id"])
+
+	b.Reset()
+	require.NoError(t, WriteGeoJson(&b, testSummary(), GeoOptions{}))
+	require.NoError(t, json.Unmarshal(b.Bytes(), &collection))
+	assert.Len(t, collection.Features, 1)
+}
+
+func TestWriteKml(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, WriteKml(&b, testSummary(), GeoOptions{Launches: true}))
+
+	var doc struct {
+		Folders []struct {
+			Name       string `xml:"name"`
+			Placemarks []struct {
+				Name        string `xml:"name"`
+				Description string `xml:"description"`
+				Coordinates string `xml:"Point>coordinates"`
+			} `xml:"Placemark"`
+		} `xml:"Document>Folder"`
+	}
+	require.NoError(t, xml.Unmarshal(b.Bytes(), &doc))
+	require.Len(t, doc.Folders, 2)
+	assert.Equal(t, "Launch sites", doc.Folders[0].Name)
+	placemark := doc.Folders[0].Placemarks[0]
+	assert.Equal(t, "Kennedy Space Center", placemark.Name)
+	assert.Equal(t, "-80.649,28.573", placemark.Coordinates)
+	assert.Contains(t, placemark.Description, "2 launches, 2 successes, 0 failures (100% success)")
+	assert.Contains(t, placemark.Description, "Top vehicles: Falcon 9 Block 5 (2)")
+
+	assert.Equal(t, "Launches", doc.Folders[1].Name)
+	assert.Equal(t, "2022-01-06 Falcon 9 Block 5", doc.Folders[1].Placemarks[0].Name)
+}
@@ -0,0 +1,214 @@
+package export
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"launchdata/config"
+	"launchdata/sites"
+)
+
+const (
+	FormatGeoJson = "geojson"
+	FormatKml     = "kml"
+)
+
+type GeoOptions struct {
+	// Launches adds a point for every launch, on top of one for every site
+	Launches bool
+}
+
+type geoJsonGeometry struct {
+	Type string `json:"type"`
+	// Coordinates are longitude then latitude
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+type geoJsonFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   geoJsonGeometry        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geoJsonFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJsonFeature `json:"features"`
+}
+
+func point(site sites.Site) geoJsonGeometry {
+	return geoJsonGeometry{Type: "Point", Coordinates: [2]float64{site.Longitude, site.Latitude}}
+}
+
+func topVehicleNames(stats sites.Stats) []string {
+	var names []string
+	for _, vehicle := range stats.TopVehicles {
+		names = append(names, fmt.Sprintf("%s (%d)", vehicle.Vehicle, vehicle.Launches))
+	}
+	return names
+}
+
+// WriteGeoJson writes a feature per site, with the site's statistics as its
+// properties. Launch points, if asked for, are told apart from sites by
+// their "layer" property.
+func WriteGeoJson(w io.Writer, summary sites.Summary, options GeoOptions) error {
+	collection := geoJsonFeatureCollection{Type: "FeatureCollection", Features: []geoJsonFeature{}}
+	for _, stats := range summary.Sites {
+		var topVehicles []map[string]interface{}
+		for _, vehicle := range stats.TopVehicles {
+			topVehicles = append(topVehicles, map[string]interface{}{"vehicle": vehicle.Vehicle, "launches": vehicle.Launches})
+		}
+		collection.Features = append(collection.Features, geoJsonFeature{
+			Type:     "Feature",
+			Geometry: point(stats.Site),
+			Properties: map[string]interface{}{
+				"layer":       "sites",
+				"name":        stats.Name,
+				"country":     stats.Country,
+				"launches":    stats.Launches,
+				"successes":   stats.Successes,
+				"failures":    stats.Failures,
+				"successRate": stats.SuccessRate,
+				"firstLaunch": stats.FirstLaunch,
+				"lastLaunch":  stats.LastLaunch,
+				"topVehicles": topVehicles,
+			},
+		})
+	}
+
+	if options.Launches {
+		for _, located := range summary.Launches {
+			launch := located.Launch
+			collection.Features = append(collection.Features, geoJsonFeature{
+				Type:     "Feature",
+				Geometry: point(located.Site),
+				Properties: map[string]interface{}{
+					"layer":     "launches",
+					"id":        launch.Id,
+					"timestamp": Timestamp(launch.Timestamp),
+					"rocket":    clean(launch.Rocket),
+					"pad":       clean(launch.LaunchSite),
+					"site":      located.Site.Name,
+					"outcome":   string(launch.Outcome()),
+				},
+			})
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(collection)
+}
+
+type kmlPoint struct {
+	// Coordinates are longitude,latitude
+	Coordinates string `xml:"coordinates"`
+}
+
+type kmlPlacemark struct {
+	Name        string   `xml:"name"`
+	Description string   `xml:"description"`
+	Point       kmlPoint `xml:"Point"`
+}
+
+type kmlFolder struct {
+	Name       string         `xml:"name"`
+	Placemarks []kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlDocument struct {
+	Name    string      `xml:"name"`
+	Folders []kmlFolder `xml:"Folder"`
+}
+
+type kml struct {
+	XMLName  xml.Name    `xml:"http://www.opengis.net/kml/2.2 kml"`
+	Document kmlDocument `xml:"Document"`
+}
+
+func kmlCoordinates(site sites.Site) kmlPoint {
+	return kmlPoint{fmt.Sprintf("%g,%g", site.Longitude, site.Latitude)}
+}
+
+// WriteKml writes a placemark per site, describing its statistics, in a
+// folder of their own. Launches, if asked for, get a second folder.
+func WriteKml(w io.Writer, summary sites.Summary, options GeoOptions) error {
+	doc := kml{Document: kmlDocument{Name: "Launch sites"}}
+
+	siteFolder := kmlFolder{Name: "Launch sites"}
+	for _, stats := range summary.Sites {
+		description := []string{
+			stats.Country,
+			fmt.Sprintf("%d launches, %d successes, %d failures (%.0f%% success)", stats.Launches, stats.Successes, stats.Failures, stats.SuccessRate*100),
+			fmt.Sprintf("First launch %s, last launch %s", stats.FirstLaunch, stats.LastLaunch),
+		}
+		if len(stats.TopVehicles) > 0 {
+			description = append(description, "Top vehicles: "+strings.Join(topVehicleNames(stats), ", "))
+		}
+		siteFolder.Placemarks = append(siteFolder.Placemarks, kmlPlacemark{
+			Name:        stats.Name,
+			Description: strings.Join(description, "\n"),
+			Point:       kmlCoordinates(stats.Site),
+		})
+	}
+	doc.Document.Folders = append(doc.Document.Folders, siteFolder)
+
+	if options.Launches {
+		launchFolder := kmlFolder{Name: "Launches"}
+		for _, located := range summary.Launches {
+			launch := located.Launch
+			launchFolder.Placemarks = append(launchFolder.Placemarks, kmlPlacemark{
+				Name:        fmt.Sprintf("%s %s", launch.Timestamp.DateString(), clean(launch.Rocket)),
+				Description: fmt.Sprintf("%s\n%s\nOutcome: %s", launch.Id, clean(launch.LaunchSite), launch.Outcome()),
+				Point:       kmlCoordinates(located.Site),
+			})
+		}
+		doc.Document.Folders = append(doc.Document.Folders, launchFolder)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteMap writes summary to filename as GeoJSON or KML
+func WriteMap(config config.Config, summary sites.Summary, format string, options GeoOptions, filename string) error {
+	if config.DryRun {
+		fmt.Printf("Dry run: would write %d sites to %s\n", len(summary.Sites), filename)
+		return nil
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case FormatGeoJson:
+		err = WriteGeoJson(f, summary, options)
+	case FormatKml:
+		err = WriteKml(f, summary, options)
+	default:
+		err = fmt.Errorf("unknown format %q for a map, expected %s or %s", format, FormatGeoJson, FormatKml)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	unlocated := 0
+	for _, launches := range summary.Unlocated {
+		unlocated += launches
+	}
+	fmt.Printf("Wrote %d sites to %s, %d launches were from sites not in the gazetteer\n", len(summary.Sites), filename, unlocated)
+	return f.Close()
+}
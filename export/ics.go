@@ -0,0 +1,301 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"launchdata/config"
+	"launchdata/parse"
+)
+
+const FormatIcs = "ics"
+
+// launchDuration is how long an event is given when we know the launch time
+// exactly. Launches don't have an end, but calendars show instants poorly.
+const launchDuration = time.Hour
+
+// window is when a launch happens, as precisely as we know it
+type window struct {
+	start time.Time
+	// end is exclusive. All day windows run from midnight to midnight.
+	end    time.Time
+	allDay bool
+}
+
+var (
+	yearRegex    = regexp.MustCompile(`\b(\d{4})\b`)
+	quarterRegex = regexp.MustCompile(`\bq([1-4])\b`)
+	halfRegex    = regexp.MustCompile(`\bh([12])\b`)
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+var monthRegexes = func() []*regexp.Regexp {
+	var regexes []*regexp.Regexp
+	for month := time.January; month <= time.December; month++ {
+		name := strings.ToLower(month.String())
+		regexes = append(regexes, regexp.MustCompile(`\b(`+name+`|`+name[:3]+`)\b`))
+	}
+	return regexes
+}()
+
+// findMonth finds a month named in lower case text, by its full name or
+// abbreviation
+func findMonth(text string) (time.Month, bool) {
+	for i, regex := range monthRegexes {
+		if regex.MatchString(text) {
+			return time.Month(i + 1), true
+		}
+	}
+	return 0, false
+}
+
+// vagueWindow reads the vague dates given to launches that haven't been
+// scheduled precisely, like "March", "Late March", "Q3" or "Mid 2024". A date
+// with no year is in year, the year of the page it came from.
+func vagueWindow(text string, year int) (window, bool) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if match := yearRegex.FindStringSubmatch(text); match != nil {
+		year, _ = strconv.Atoi(match[1])
+	}
+
+	early := strings.Contains(text, "early")
+	mid := strings.Contains(text, "mid")
+	late := strings.Contains(text, "late")
+
+	if month, ok := findMonth(text); ok {
+		start, end := date(year, month, 1), date(year, month+1, 1)
+		switch {
+		case early:
+			end = date(year, month, 11)
+		case mid:
+			start, end = date(year, month, 11), date(year, month, 21)
+		case late:
+			start = date(year, month, 21)
+		}
+		return window{start, end, true}, true
+	}
+
+	if match := quarterRegex.FindStringSubmatch(text); match != nil {
+		quarter, _ := strconv.Atoi(match[1])
+		first := time.Month(quarter*3 - 2)
+		return window{date(year, first, 1), date(year, first+3, 1), true}, true
+	}
+	if match := halfRegex.FindStringSubmatch(text); match != nil {
+		half, _ := strconv.Atoi(match[1])
+		first := time.Month(half*6 - 5)
+		return window{date(year, first, 1), date(year, first+6, 1), true}, true
+	}
+
+	switch {
+	case early:
+		return window{date(year, time.January, 1), date(year, time.May, 1), true}, true
+	case mid:
+		return window{date(year, time.May, 1), date(year, time.September, 1), true}, true
+	case late:
+		return window{date(year, time.September, 1), date(year+1, time.January, 1), true}, true
+	}
+
+	// A bare year, or nothing at all, is too vague to be worth a calendar entry
+	return window{}, false
+}
+
+func launchWindow(t parse.TimeData, year int) (window, bool) {
+	if !t.ParsedOk {
+		return vagueWindow(t.TimestampClean, year)
+	}
+
+	// Dates without a time are parsed as midnight
+	if !strings.Contains(t.TimestampClean, ":") {
+		day := t.Timestamp.UTC().Truncate(24 * time.Hour)
+		return window{day, day.AddDate(0, 0, 1), true}, true
+	}
+	return window{t.Timestamp.UTC(), t.Timestamp.UTC().Add(launchDuration), false}, true
+}
+
+type event struct {
+	uid         string
+	window      window
+	summary     string
+	location    string
+	description string
+}
+
+// Calendar collects launches into an iCalendar feed
+type Calendar struct {
+	// Now is written as the time each event was generated
+	Now    time.Time
+	events []event
+	// Skipped counts the launches whose dates were too vague to add
+	Skipped int
+}
+
+func NewCalendar(now time.Time) *Calendar {
+	return &Calendar{Now: now}
+}
+
+func eventSummary(launch parse.RocketData) string {
+	summary := clean(launch.Rocket)
+	if len(launch.Payload) > 0 && clean(launch.Payload[0].Payload) != "" {
+		summary += " | " + clean(launch.Payload[0].Payload)
+		if len(launch.Payload) > 1 {
+			summary += fmt.Sprintf(" and %d more", len(launch.Payload)-1)
+		}
+	} else if clean(launch.FlightNumber) != "" {
+		summary += " | " + clean(launch.FlightNumber)
+	}
+	return summary
+}
+
+func eventDescription(launch parse.RocketData) string {
+	var lines []string
+	if provider := clean(launch.LaunchServiceProvider); provider != "" {
+		lines = append(lines, "Provider: "+provider)
+	}
+	lines = append(lines, "Outcome: "+string(launch.Outcome()))
+	if len(launch.Payload) > 0 {
+		lines = append(lines, "", "Payloads:")
+		for _, payload := range launch.Payload {
+			var details []string
+			for _, detail := range []string{payload.Operator, payload.Orbit, payload.Outcome} {
+				if clean(detail) != "" {
+					details = append(details, clean(detail))
+				}
+			}
+			name := clean(payload.Payload)
+			if name == "" {
+				name = "Unnamed"
+			}
+			line := "- " + name
+			if len(details) > 0 {
+				line += " (" + strings.Join(details, ", ") + ")"
+			}
+			lines = append(lines, line)
+		}
+	}
+	if notes := clean(launch.Notes); notes != "" {
+		lines = append(lines, "", notes)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// eventUid identifies a launch's event by the year it's cached under and its
+// id within that year's file. Neither depends on which years are exported,
+// and ids are stable across refreshes, so calendars update the event instead
+// of adding another. Ids from dataset.Stream already start with the year.
+func eventUid(year int, id string) string {
+	prefix := strconv.Itoa(year) + "/"
+	if !strings.HasPrefix(id, prefix) {
+		id = prefix + id
+	}
+	return id + "@launchdata"
+}
+
+// Add adds launch, which is cached under year, to the calendar. Launches with
+// no date more precise than a year are skipped.
+func (c *Calendar) Add(year int, launch parse.RocketData) bool {
+	if launch.IsSpurious() {
+		return false
+	}
+	w, ok := launchWindow(launch.Timestamp, year)
+	if !ok {
+		c.Skipped++
+		return false
+	}
+
+	c.events = append(c.events, event{
+		uid:         eventUid(year, launch.Id),
+		window:      w,
+		summary:     eventSummary(launch),
+		location:    clean(launch.LaunchSite),
+		description: eventDescription(launch),
+	})
+	return true
+}
+
+// escapeText escapes a TEXT value as RFC 5545 section 3.3.11 describes
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// writeLine writes a content line, folded to 75 octets as RFC 5545 section
+// 3.1 asks, without splitting a character
+func writeLine(w *bufio.Writer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space of the continuation counts towards its length
+		limit = 74
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
+
+func (e event) write(w *bufio.Writer, stamp string) {
+	writeLine(w, "BEGIN:VEVENT")
+	writeLine(w, "UID:"+escapeText(e.uid))
+	writeLine(w, "DTSTAMP:"+stamp)
+	if e.window.allDay {
+		writeLine(w, "DTSTART;VALUE=DATE:"+e.window.start.Format("20060102"))
+		writeLine(w, "DTEND;VALUE=DATE:"+e.window.end.Format("20060102"))
+	} else {
+		writeLine(w, "DTSTART:"+e.window.start.Format("20060102T150405Z"))
+		writeLine(w, "DTEND:"+e.window.end.Format("20060102T150405Z"))
+	}
+	writeLine(w, "SUMMARY:"+escapeText(e.summary))
+	if e.location != "" {
+		writeLine(w, "LOCATION:"+escapeText(e.location))
+	}
+	writeLine(w, "DESCRIPTION:"+escapeText(e.description))
+	writeLine(w, "END:VEVENT")
+}
+
+// Write writes the calendar as RFC 5545 iCalendar
+func (c *Calendar) Write(out io.Writer) error {
+	w := bufio.NewWriter(out)
+	writeLine(w, "BEGIN:VCALENDAR")
+	writeLine(w, "VERSION:2.0")
+	writeLine(w, "PRODID:-//launchdata//launchdata//EN")
+	writeLine(w, "CALSCALE:GREGORIAN")
+	writeLine(w, "X-WR-CALNAME:Orbital launches")
+	stamp := c.Now.UTC().Format("20060102T150405Z")
+	for _, e := range c.events {
+		e.write(w, stamp)
+	}
+	writeLine(w, "END:VCALENDAR")
+	return w.Flush()
+}
+
+// WriteIcs writes calendar to filename
+func WriteIcs(config config.Config, calendar *Calendar, filename string) error {
+	if config.DryRun {
+		fmt.Printf("Dry run: would write %d events to %s\n", len(calendar.events), filename)
+		return nil
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := calendar.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	fmt.Printf("Wrote %d events to %s, skipping %d launches without a precise enough date\n", len(calendar.events), filename, calendar.Skipped)
+	return f.Close()
+}
@@ -0,0 +1,32 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+
+	"launchdata/parse"
+)
+
+const FormatNdjson = "ndjson"
+
+// NdjsonWriter writes launches one per line as they're given to it, so an
+// export never has to hold more than one launch
+type NdjsonWriter struct {
+	encoder *json.Encoder
+	// Count is how many launches have been written
+	Count int
+}
+
+func NewNdjsonWriter(w io.Writer) *NdjsonWriter {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return &NdjsonWriter{encoder: encoder}
+}
+
+func (n *NdjsonWriter) Write(launch parse.RocketData) error {
+	if err := n.encoder.Encode(launch); err != nil {
+		return err
+	}
+	n.Count++
+	return nil
+}
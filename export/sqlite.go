@@ -0,0 +1,319 @@
+package export
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"launchdata/config"
+	"launchdata/parse"
+
+	_ "modernc.org/sqlite"
+)
+
+const FormatSqlite = "sqlite"
+
+// schema is the layout of the sqlite export. Vehicles, sites and
+// organizations are named once and referred to by id, and launches_flat joins
+// them back up for quick queries.
+const schema = `
+CREATE TABLE organizations (
+	id INTEGER PRIMARY KEY,
+	name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE sites (
+	id INTEGER PRIMARY KEY,
+	name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE vehicles (
+	id INTEGER PRIMARY KEY,
+	name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE launches (
+	id TEXT PRIMARY KEY,
+	year INTEGER NOT NULL,
+	date TEXT NOT NULL,
+	timestamp TEXT NOT NULL,
+	timestamp_parsed INTEGER NOT NULL,
+	tbd INTEGER NOT NULL,
+	vehicle_id INTEGER REFERENCES vehicles(id),
+	flight_number TEXT,
+	site_id INTEGER REFERENCES sites(id),
+	provider_id INTEGER REFERENCES organizations(id),
+	outcome TEXT NOT NULL,
+	boosters TEXT,
+	payload_mass TEXT,
+	customer TEXT,
+	cospar_id TEXT,
+	gcat_launch_tag TEXT,
+	notes TEXT
+);
+
+CREATE INDEX launches_year ON launches(year);
+CREATE INDEX launches_vehicle_id ON launches(vehicle_id);
+CREATE INDEX launches_site_id ON launches(site_id);
+CREATE INDEX launches_provider_id ON launches(provider_id);
+CREATE INDEX launches_outcome ON launches(outcome);
+
+CREATE TABLE payloads (
+	id INTEGER PRIMARY KEY,
+	launch_id TEXT NOT NULL REFERENCES launches(id),
+	idx INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	operator_id INTEGER REFERENCES organizations(id),
+	orbit TEXT,
+	function TEXT,
+	decay TEXT,
+	outcome TEXT,
+	cubesat INTEGER NOT NULL,
+	norad_id INTEGER,
+	intl_designator TEXT,
+	object_type TEXT,
+	status TEXT,
+	decay_date TEXT,
+	UNIQUE (launch_id, idx)
+);
+
+CREATE INDEX payloads_operator_id ON payloads(operator_id);
+CREATE INDEX payloads_norad_id ON payloads(norad_id);
+
+CREATE VIEW launches_flat AS
+SELECT
+	l.id,
+	l.year,
+	l.date,
+	l.timestamp,
+	l.timestamp_parsed,
+	l.tbd,
+	v.name AS vehicle,
+	l.flight_number,
+	s.name AS site,
+	o.name AS provider,
+	l.outcome,
+	(SELECT count(*) FROM payloads p WHERE p.launch_id = l.id) AS payloads,
+	l.boosters,
+	l.payload_mass,
+	l.customer,
+	l.cospar_id,
+	l.gcat_launch_tag,
+	l.notes
+FROM launches l
+LEFT JOIN vehicles v ON v.id = l.vehicle_id
+LEFT JOIN sites s ON s.id = l.site_id
+LEFT JOIN organizations o ON o.id = l.provider_id;
+`
+
+// OpenDatabase opens the sqlite database at filename, which can be ":memory:",
+// with foreign keys enforced
+func OpenDatabase(filename string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", "file:"+filename+"?_pragma=foreign_keys(1)")
+	if err != nil {
+		return nil, err
+	}
+	// Each connection to ":memory:" would get its own empty database
+	db.SetMaxOpenConns(1)
+	return db, nil
+}
+
+// nullString is NULL for an empty string, so missing values aren't counted
+// as a name
+func nullString(s string) sql.NullString {
+	s = strings.TrimSpace(s)
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// names gives each distinct name in a lookup table an id
+type names struct {
+	table string
+	ids   map[string]int64
+	stmt  *sql.Stmt
+}
+
+func newNames(tx *sql.Tx, table string) (*names, error) {
+	stmt, err := tx.Prepare(fmt.Sprintf("INSERT INTO %s (name) VALUES (?)", table))
+	if err != nil {
+		return nil, err
+	}
+	return &names{table: table, ids: map[string]int64{}, stmt: stmt}, nil
+}
+
+func (n *names) id(name string) (sql.NullInt64, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return sql.NullInt64{}, nil
+	}
+	if id, ok := n.ids[name]; ok {
+		return sql.NullInt64{Int64: id, Valid: true}, nil
+	}
+
+	result, err := n.stmt.Exec(name)
+	if err != nil {
+		return sql.NullInt64{}, fmt.Errorf("adding %q to %s: %w", name, n.table, err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return sql.NullInt64{}, err
+	}
+	n.ids[name] = id
+	return sql.NullInt64{Int64: id, Valid: true}, nil
+}
+
+// Launches calls add for every launch to go in a database, along with the
+// year it's cached under, the way dataset.Stream does
+type Launches func(add func(year int, launch parse.RocketData) error) error
+
+// LaunchesIn is launches, all cached under year
+func LaunchesIn(year int, launches []parse.RocketData) Launches {
+	return func(add func(year int, launch parse.RocketData) error) error {
+		for _, launch := range launches {
+			if err := add(year, launch); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// CreateDatabase creates the export's tables in db and fills them with
+// launches
+func CreateDatabase(db *sql.DB, launches Launches) error {
+	if _, err := db.Exec(schema); err != nil {
+		return fmt.Errorf("creating tables: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := insertLaunches(tx, launches); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertLaunches(tx *sql.Tx, launches Launches) error {
+	organizations, err := newNames(tx, "organizations")
+	if err != nil {
+		return err
+	}
+	sites, err := newNames(tx, "sites")
+	if err != nil {
+		return err
+	}
+	vehicles, err := newNames(tx, "vehicles")
+	if err != nil {
+		return err
+	}
+
+	insertLaunch, err := tx.Prepare(`INSERT INTO launches (id, year, date, timestamp, timestamp_parsed, tbd,
+		vehicle_id, flight_number, site_id, provider_id, outcome, boosters, payload_mass, customer,
+		cospar_id, gcat_launch_tag, notes) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	insertPayload, err := tx.Prepare(`INSERT INTO payloads (launch_id, idx, name, operator_id, orbit,
+		function, decay, outcome, cubesat, norad_id, intl_designator, object_type, status, decay_date)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+
+	return launches(func(year int, launch parse.RocketData) error {
+		vehicle, err := vehicles.id(launch.Rocket)
+		if err != nil {
+			return err
+		}
+		site, err := sites.id(launch.LaunchSite)
+		if err != nil {
+			return err
+		}
+		provider, err := organizations.id(launch.LaunchServiceProvider)
+		if err != nil {
+			return err
+		}
+
+		if _, err := insertLaunch.Exec(launch.Id, year, clean(launch.Timestamp.DateString()), Timestamp(launch.Timestamp),
+			launch.Timestamp.ParsedOk, launch.Timestamp.Tbd, vehicle, nullString(launch.FlightNumber), site, provider,
+			string(launch.Outcome()), nullString(boosterSerials(launch)), nullString(launch.PayloadMass),
+			nullString(launch.Customer), nullString(launch.CosparId), nullString(launch.GcatLaunchTag),
+			nullString(launch.Notes)); err != nil {
+			return fmt.Errorf("adding launch %s: %w", launch.Id, err)
+		}
+
+		for i, payload := range launch.Payload {
+			operator, err := organizations.id(payload.Operator)
+			if err != nil {
+				return err
+			}
+
+			var noradId sql.NullInt64
+			if payload.NoradId != 0 {
+				noradId = sql.NullInt64{Int64: int64(payload.NoradId), Valid: true}
+			}
+
+			if _, err := insertPayload.Exec(launch.Id, i, clean(payload.Payload), operator, nullString(payload.Orbit),
+				nullString(payload.Function), nullString(payload.Decay), nullString(payload.Outcome), payload.Cubesat,
+				noradId, nullString(payload.IntlDesignator), nullString(payload.ObjectType), nullString(payload.Status),
+				nullString(payload.DecayDate)); err != nil {
+				return fmt.Errorf("adding payload %d of launch %s: %w", i, launch.Id, err)
+			}
+		}
+		return nil
+	})
+}
+
+// WriteSqlite writes launches to a new sqlite database at filename, replacing
+// any database already there
+func WriteSqlite(config config.Config, launches Launches, filename string) error {
+	if config.DryRun {
+		count := 0
+		err := launches(func(year int, launch parse.RocketData) error {
+			count++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Dry run: would write %d launches to %s\n", count, filename)
+		return nil
+	}
+
+	// Build it next to the output and move it into place, so a failed export
+	// doesn't leave half a database behind
+	f, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	f.Close()
+	defer os.Remove(tmp)
+
+	db, err := OpenDatabase(tmp)
+	if err != nil {
+		return err
+	}
+	if err := CreateDatabase(db, launches); err != nil {
+		db.Close()
+		return err
+	}
+	var count int
+	if err := db.QueryRow("SELECT count(*) FROM launches").Scan(&count); err != nil {
+		db.Close()
+		return err
+	}
+	if err := db.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, filename); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %d launches to %s\n", count, filename)
+	return nil
+}
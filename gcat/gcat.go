@@ -0,0 +1,147 @@
+// Package gcat reads the launch log from Jonathan McDowell's General
+// Catalog of Artificial Space Objects (https://planet4589.org/space/gcat/),
+// and reconciles it with the launches we parsed from wikipedia.
+package gcat
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Launch is a row of launchlog.tsv. Only the columns we use are kept.
+type Launch struct {
+	Tag  string
+	Date time.Time
+	// DateHasTime is false when GCAT only knows the day of the launch
+	DateHasTime bool
+	DateRaw     string
+	Vehicle     string
+	Variant     string
+	Mission     string
+	Site        string
+	Pad         string
+	// Code is GCAT's launch code, e.g. "OS" for an orbital success or "OF"
+	// for an orbital failure
+	Code string
+}
+
+func (l Launch) Orbital() bool {
+	return strings.HasPrefix(l.Code, "O")
+}
+
+func (l Launch) Failed() bool {
+	return len(l.Code) > 1 && l.Code[1] == 'F'
+}
+
+func (l Launch) Succeeded() bool {
+	return len(l.Code) > 1 && l.Code[1] == 'S'
+}
+
+var cosparLaunchRegex = regexp.MustCompile(`^\d{4}-\d{3}$`)
+
+// CosparId is the international designator of the launch, e.g. "2023-001".
+// Failed launches don't get one, and GCAT tags them like "2023-F01" instead.
+func (l Launch) CosparId() string {
+	if cosparLaunchRegex.MatchString(l.Tag) {
+		return l.Tag
+	}
+	return ""
+}
+
+var spacesRegex = regexp.MustCompile(`\s+`)
+
+var dateFormats = []struct {
+	layout  string
+	hasTime bool
+}{
+	{"2006 Jan 2 1504:05.999999999", true},
+	{"2006 Jan 2 1504:05", true},
+	{"2006 Jan 2 1504", true},
+	{"2006 Jan 2 15", true},
+	{"2006 Jan 2", false},
+}
+
+// parseDate reads GCAT's dates, like "1957 Oct  4 1928:34". Uncertain parts
+// are marked with a "?", which we ignore.
+func parseDate(raw string) (time.Time, bool, error) {
+	cleaned := strings.TrimSpace(spacesRegex.ReplaceAllString(strings.ReplaceAll(raw, "?", ""), " "))
+	for _, format := range dateFormats {
+		if t, err := time.Parse(format.layout, cleaned); err == nil {
+			return t, format.hasTime, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("can't parse date %q", raw)
+}
+
+// Load reads a launch log in GCAT's TSV format. Lines starting with "#" are
+// comments, apart from the first, which names the columns.
+func Load(filename string) ([]Launch, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var columns map[string]int
+	var launches []Launch
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if columns == nil {
+			columns = map[string]int{}
+			for i, name := range strings.Split(strings.TrimPrefix(text, "#"), "\t") {
+				columns[strings.TrimSpace(name)] = i
+			}
+			for _, name := range []string{"Launch_Tag", "Launch_Date", "LV_Type", "Launch_Code"} {
+				if _, ok := columns[name]; !ok {
+					return nil, fmt.Errorf("%s doesn't look like a GCAT launch log, it has no %s column", filename, name)
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(text, "#") || strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		fields := strings.Split(text, "\t")
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(fields) {
+				return ""
+			}
+			return strings.TrimSpace(fields[i])
+		}
+
+		date, hasTime, err := parseDate(field("Launch_Date"))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", filename, line, err)
+		}
+
+		launches = append(launches, Launch{
+			Tag:         field("Launch_Tag"),
+			Date:        date,
+			DateHasTime: hasTime,
+			DateRaw:     field("Launch_Date"),
+			Vehicle:     field("LV_Type"),
+			Variant:     field("Variant"),
+			Mission:     field("Mission"),
+			Site:        field("Launch_Site"),
+			Pad:         field("Launch_Pad"),
+			Code:        field("Launch_Code"),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if columns == nil {
+		return nil, fmt.Errorf("%s is empty", filename)
+	}
+
+	return launches, nil
+}
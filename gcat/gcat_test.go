@@ -0,0 +1,93 @@
+package gcat
+
+import (
+	"testing"
+	"time"
+
+	"launchdata/parse"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	log, err := Load("testdata/launchlog.tsv")
+	require.NoError(t, err)
+	require.Len(t, log, 5)
+
+	assert.Equal(t, "2023-001", log[0].Tag)
+	assert.Equal(t, time.Date(2023, 1, 3, 14, 56, 43, 0, time.UTC), log[0].Date)
+	assert.True(t, log[0].DateHasTime)
+	assert.Equal(t, "Falcon 9", log[0].Vehicle)
+	assert.Equal(t, "2023-001", log[0].CosparId())
+	assert.True(t, log[0].Succeeded())
+
+	assert.Equal(t, time.Date(2023, 1, 9, 10, 50, 0, 0, time.UTC), log[1].Date)
+	assert.True(t, log[2].Failed())
+	assert.Empty(t, log[2].CosparId())
+	assert.False(t, log[3].Orbital())
+	assert.False(t, log[4].DateHasTime)
+}
+
+func TestLoadRejectsOtherFiles(t *testing.T) {
+	_, err := Load("gcat.go")
+	assert.Error(t, err)
+}
+
+func launch(id string, rocket string, timestamp string, outcome string) parse.RocketData {
+	t, err := time.Parse("2006-01-02 15:04", timestamp)
+	if err != nil {
+		panic(err)
+	}
+	clean := timestamp
+	if t.Hour() == 0 && t.Minute() == 0 {
+		clean = t.Format("2 January")
+	}
+	return parse.RocketData{
+		Id:        id,
+		Rocket:    rocket,
+		Timestamp: parse.TimeData{TimestampClean: clean, Timestamp: t, ParsedOk: true},
+		Payload:   []parse.PayloadData{{Outcome: outcome}},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	log, err := Load("testdata/launchlog.tsv")
+	require.NoError(t, err)
+
+	launches := []parse.RocketData{
+		launch("falcon-9/197", "Falcon 9 Block 5", "2023-01-03 14:56", "Operational"),
+		launch("long-march-7a/y6", "Long March 7A", "2023-01-09 11:30", "Operational"),
+		launch("launcherone/start-me-up", "LauncherOne", "2023-01-10 23:01", "Operational"),
+		launch("pslv/c55", "PSLV-CA", "2023-01-12 09:00", "Operational"),
+		// Launched after the end of the log, so it isn't missing
+		launch("electron/later", "Electron", "2023-03-01 00:00", "Operational"),
+	}
+
+	report := Reconcile(launches, log, []int{2023}, time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), DefaultOptions)
+
+	assert.Equal(t, 3, report.Matched)
+	assert.Equal(t, []Missing{{LaunchId: "pslv/c55", Date: "2023-01-12", Vehicle: "PSLV-CA"}}, report.MissingFromGcat)
+	assert.Equal(t, []Missing{{GcatTag: "2023-004", Date: "2023-01-15", Vehicle: "Electron"}}, report.MissingFromOurs)
+
+	require.Len(t, report.TimestampDisagreements, 1)
+	assert.Equal(t, "long-march-7a/y6", report.TimestampDisagreements[0].LaunchId)
+
+	require.Len(t, report.OutcomeDisagreements, 1)
+	assert.Equal(t, Disagreement{LaunchId: "launcherone/start-me-up", GcatTag: "2023-F01", Ours: "launch success", Gcat: "launch failure"}, report.OutcomeDisagreements[0])
+
+	Enrich(launches, report)
+	assert.Equal(t, "2023-001", launches[0].GcatLaunchTag)
+	assert.Equal(t, "2023-001", launches[0].CosparId)
+	assert.Equal(t, "2023-F01", launches[2].GcatLaunchTag)
+	assert.Empty(t, launches[2].CosparId)
+	assert.Empty(t, launches[3].GcatLaunchTag)
+}
+
+func TestVehicleMatches(t *testing.T) {
+	assert.True(t, vehicleMatches("Falcon 9 Block 5", "Falcon 9"))
+	assert.True(t, vehicleMatches("Long March 2D", "Chang Zheng 2D"))
+	assert.True(t, vehicleMatches("Soyuz-2.1a", "Soyuz-2-1A"))
+	assert.False(t, vehicleMatches("Falcon Heavy", "Falcon 9"))
+	assert.False(t, vehicleMatches("", "Falcon 9"))
+}
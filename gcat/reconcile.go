@@ -0,0 +1,236 @@
+package gcat
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"launchdata/parse"
+)
+
+type Options struct {
+	// Window is how far apart two launches can be and still be matched. Our
+	// launches sometimes only have a date, so this needs to be over a day.
+	Window time.Duration
+
+	// Tolerance is how far apart the times of a matched launch can be before
+	// they're reported as disagreeing, when both sides know the time
+	Tolerance time.Duration
+}
+
+var DefaultOptions = Options{
+	Window:    36 * time.Hour,
+	Tolerance: 5 * time.Minute,
+}
+
+// Match pairs one of our launches, by its index in the launches given to
+// Reconcile, with a GCAT launch
+type Match struct {
+	Ours   int
+	Theirs Launch
+}
+
+// Missing is a launch found on only one side
+type Missing struct {
+	LaunchId string `json:",omitempty"`
+	GcatTag  string `json:",omitempty"`
+	Date     string
+	Vehicle  string
+}
+
+// Disagreement is a launch both sides have but describe differently
+type Disagreement struct {
+	LaunchId string
+	GcatTag  string
+	Ours     string
+	Gcat     string
+}
+
+type Report struct {
+	Matches                []Match `json:"-"`
+	Matched                int
+	MissingFromGcat        []Missing
+	MissingFromOurs        []Missing
+	TimestampDisagreements []Disagreement
+	OutcomeDisagreements   []Disagreement
+}
+
+var (
+	vehicleAliases = strings.NewReplacer("chang zheng", "long march")
+	nonAlnumRegex  = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+func normaliseVehicle(vehicle string) string {
+	return nonAlnumRegex.ReplaceAllString(vehicleAliases.Replace(strings.ToLower(vehicle)), "")
+}
+
+// vehicleMatches compares vehicles loosely, since wikipedia usually names the
+// variant, e.g. "Falcon 9 Block 5" for GCAT's "Falcon 9"
+func vehicleMatches(ours string, theirs string) bool {
+	a, b := normaliseVehicle(ours), normaliseVehicle(theirs)
+	if a == "" || b == "" {
+		return false
+	}
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+// hasTime reports whether wikipedia gave a time for the launch, rather than
+// just a date
+func hasTime(t parse.TimeData) bool {
+	return strings.Contains(t.TimestampClean, ":")
+}
+
+func gap(a time.Time, b time.Time) time.Duration {
+	d := a.Sub(b)
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+type candidate struct {
+	ours, theirs int
+	gap          time.Duration
+}
+
+// Reconcile matches our orbital launches with GCAT's by time and vehicle.
+// Only launches that have already happened are compared, and only GCAT
+// launches in years, so a partial cache doesn't look like it's missing
+// everything else.
+func Reconcile(launches []parse.RocketData, log []Launch, years []int, now time.Time, options Options) Report {
+	inYears := map[int]bool{}
+	for _, year := range years {
+		inYears[year] = true
+	}
+
+	var theirs []Launch
+	var lastGcat time.Time
+	for _, launch := range log {
+		if launch.Orbital() && inYears[launch.Date.Year()] {
+			theirs = append(theirs, launch)
+		}
+		if launch.Date.After(lastGcat) {
+			lastGcat = launch.Date
+		}
+	}
+
+	// Anything after the end of the log just hasn't made it in yet
+	var ours []int
+	for i, launch := range launches {
+		if launch.Timestamp.LaunchedAlready(now) && !launch.Timestamp.Timestamp.After(lastGcat) {
+			ours = append(ours, i)
+		}
+	}
+
+	var sameVehicle, otherVehicle []candidate
+	for _, i := range ours {
+		for j, launch := range theirs {
+			g := gap(launches[i].Timestamp.Timestamp, launch.Date)
+			switch {
+			case g <= options.Window && vehicleMatches(launches[i].Rocket, launch.Vehicle):
+				sameVehicle = append(sameVehicle, candidate{i, j, g})
+			case g <= options.Tolerance && hasTime(launches[i].Timestamp) && launch.DateHasTime:
+				// Launches at the same minute are the same launch, even if
+				// the vehicles are named too differently to match
+				otherVehicle = append(otherVehicle, candidate{i, j, g})
+			}
+		}
+	}
+
+	var report Report
+	matchedOurs := map[int]bool{}
+	matchedTheirs := map[int]bool{}
+	for _, candidates := range [][]candidate{sameVehicle, otherVehicle} {
+		sort.SliceStable(candidates, func(a, b int) bool { return candidates[a].gap < candidates[b].gap })
+		for _, c := range candidates {
+			if matchedOurs[c.ours] || matchedTheirs[c.theirs] {
+				continue
+			}
+			matchedOurs[c.ours] = true
+			matchedTheirs[c.theirs] = true
+			report.Matches = append(report.Matches, Match{Ours: c.ours, Theirs: theirs[c.theirs]})
+		}
+	}
+	sort.Slice(report.Matches, func(a, b int) bool { return report.Matches[a].Ours < report.Matches[b].Ours })
+	report.Matched = len(report.Matches)
+
+	for _, i := range ours {
+		if !matchedOurs[i] {
+			report.MissingFromGcat = append(report.MissingFromGcat, Missing{
+				LaunchId: launches[i].Id,
+				Date:     launches[i].Timestamp.DateString(),
+				Vehicle:  strings.TrimSpace(launches[i].Rocket),
+			})
+		}
+	}
+	for j, launch := range theirs {
+		if !matchedTheirs[j] {
+			report.MissingFromOurs = append(report.MissingFromOurs, Missing{
+				GcatTag: launch.Tag,
+				Date:    launch.Date.Format("2006-01-02"),
+				Vehicle: launch.Vehicle,
+			})
+		}
+	}
+
+	for _, match := range report.Matches {
+		launch := launches[match.Ours]
+		if disagreement, ok := compareTimestamps(launch, match.Theirs, options); ok {
+			report.TimestampDisagreements = append(report.TimestampDisagreements, disagreement)
+		}
+		if disagreement, ok := compareOutcomes(launch, match.Theirs); ok {
+			report.OutcomeDisagreements = append(report.OutcomeDisagreements, disagreement)
+		}
+	}
+
+	return report
+}
+
+func compareTimestamps(launch parse.RocketData, theirs Launch, options Options) (Disagreement, bool) {
+	ours := launch.Timestamp
+	disagreement := Disagreement{LaunchId: launch.Id, GcatTag: theirs.Tag, Ours: ours.TimestampClean, Gcat: theirs.DateRaw}
+
+	if hasTime(ours) && theirs.DateHasTime {
+		return disagreement, gap(ours.Timestamp, theirs.Date) > options.Tolerance
+	}
+	return disagreement, ours.Timestamp.Format("2006-01-02") != theirs.Date.Format("2006-01-02")
+}
+
+// compareOutcomes only compares whether the rocket did its job, since GCAT's
+// launch codes don't say what happened to the payloads afterwards
+func compareOutcomes(launch parse.RocketData, theirs Launch) (Disagreement, bool) {
+	var oursFailed bool
+	switch launch.Outcome() {
+	case parse.OutcomeSuccess, parse.OutcomeSpacecraftFailure:
+		oursFailed = false
+	case parse.OutcomeLaunchFailure:
+		oursFailed = true
+	default:
+		return Disagreement{}, false
+	}
+	if !theirs.Failed() && !theirs.Succeeded() {
+		return Disagreement{}, false
+	}
+
+	describe := func(failed bool) string {
+		if failed {
+			return "launch failure"
+		}
+		return "launch success"
+	}
+	return Disagreement{
+		LaunchId: launch.Id,
+		GcatTag:  theirs.Tag,
+		Ours:     describe(oursFailed),
+		Gcat:     describe(theirs.Failed()),
+	}, oursFailed != theirs.Failed()
+}
+
+// Enrich copies GCAT's launch tag and COSPAR id onto each matched launch
+func Enrich(launches []parse.RocketData, report Report) {
+	for _, match := range report.Matches {
+		launches[match.Ours].GcatLaunchTag = match.Theirs.Tag
+		launches[match.Ours].CosparId = match.Theirs.CosparId()
+	}
+}
@@ -0,0 +1,142 @@
+package history
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const FeedFilename = "feed.atom"
+
+// feedLength is how many of the latest events the feed carries
+const feedLength = 100
+
+func FeedPath(dir string) string {
+	return filepath.Join(dir, FeedFilename)
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	Id       string       `xml:"id"`
+	Title    string       `xml:"title"`
+	Updated  string       `xml:"updated"`
+	Category atomCategory `xml:"category"`
+	Content  atomContent  `xml:"content"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Id      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// EntryId identifies the event's entry. It only depends on the event, so
+// feed readers don't show an entry twice when the feed is rewritten.
+func (e Event) EntryId() string {
+	if e.Kind == EventOutcome {
+		return fmt.Sprintf("urn:launchdata:launch:%s:outcome:%s", e.Id, e.ObservedAt.UTC().Format("20060102T150405Z"))
+	}
+	return fmt.Sprintf("urn:launchdata:launch:%s:%s", e.Id, e.Kind)
+}
+
+func (e Event) describe() string {
+	name := e.Rocket
+	if len(e.Payloads) > 0 {
+		name += " | " + e.Payloads[0]
+	}
+	return name
+}
+
+func (e Event) title() string {
+	if e.Kind == EventOutcome {
+		return fmt.Sprintf("%s: %s → %s", e.describe(), e.PreviousOutcome, e.Outcome)
+	}
+	return "New launch: " + e.describe()
+}
+
+func (e Event) content() string {
+	lines := []string{
+		"Launch time: " + e.Timestamp,
+		"Vehicle: " + e.Rocket,
+	}
+	if len(e.Payloads) > 0 {
+		lines = append(lines, "Payloads: "+strings.Join(e.Payloads, ", "))
+	}
+	if e.Kind == EventOutcome {
+		lines = append(lines, fmt.Sprintf("Outcome: %s, was %s", e.Outcome, e.PreviousOutcome))
+	} else {
+		lines = append(lines, fmt.Sprintf("Outcome: %s", e.Outcome))
+	}
+	lines = append(lines, "Id: "+e.Id)
+	return strings.Join(lines, "\n")
+}
+
+// WriteFeed writes events as an Atom feed. updated is used for the feed when
+// there are no events to take it from.
+func WriteFeed(w io.Writer, events []Event, updated time.Time) error {
+	for i, event := range events {
+		if i == 0 || event.ObservedAt.After(updated) {
+			updated = event.ObservedAt
+		}
+	}
+
+	feed := atomFeed{
+		Id:      "urn:launchdata:feed",
+		Title:   "launchdata: new launches and outcomes",
+		Updated: atomTime(updated),
+		Author:  atomAuthor{Name: "launchdata"},
+	}
+	for _, event := range events {
+		feed.Entries = append(feed.Entries, atomEntry{
+			Id:       event.EntryId(),
+			Title:    event.title(),
+			Updated:  atomTime(event.ObservedAt),
+			Category: atomCategory{Term: string(event.Kind)},
+			Content:  atomContent{Type: "text", Text: event.content()},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(feed); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// writeFeedFile writes the latest events to filename
+func (h History) writeFeedFile(filename string, now time.Time) error {
+	var b bytes.Buffer
+	if err := WriteFeed(&b, h.Latest(feedLength), now); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, b.Bytes(), 0644)
+}
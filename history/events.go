@@ -0,0 +1,104 @@
+package history
+
+import (
+	"strings"
+	"time"
+
+	"launchdata/parse"
+)
+
+type EventKind string
+
+const (
+	// EventAdded is a launch appearing for the first time
+	EventAdded EventKind = "added"
+	// EventOutcome is a launch's outcome changing, e.g. from planned to
+	// success
+	EventOutcome EventKind = "outcome"
+)
+
+// Event is a change to the dataset noticed during a refresh
+type Event struct {
+	Kind       EventKind
+	ObservedAt time.Time
+	Id         string
+	// Timestamp is RFC 3339 when it could be parsed, and whatever wikipedia
+	// said otherwise, e.g. "Q3"
+	Timestamp       string
+	Rocket          string
+	Payloads        []string
+	Outcome         parse.Outcome
+	PreviousOutcome parse.Outcome `json:",omitempty"`
+}
+
+func newEvent(kind EventKind, observedAt time.Time, launch parse.RocketData) Event {
+	event := Event{
+		Kind:       kind,
+		ObservedAt: observedAt,
+		Id:         launch.Id,
+		Timestamp:  strings.TrimSpace(launch.Timestamp.TimestampClean),
+		Rocket:     strings.TrimSpace(launch.Rocket),
+		Outcome:    launch.Outcome(),
+	}
+	if launch.Timestamp.ParsedOk {
+		event.Timestamp = launch.Timestamp.Timestamp.UTC().Format(time.RFC3339)
+	}
+	for _, payload := range launch.Payload {
+		if name := strings.TrimSpace(payload.Payload); name != "" {
+			event.Payloads = append(event.Payloads, name)
+		}
+	}
+	return event
+}
+
+// RecordEvents adds an event for every launch in file that wasn't there
+// before and every launch whose outcome changed. The first time a file is
+// recorded its launches are only learnt, otherwise a new history would
+// announce every launch there has ever been.
+func (h *History) RecordEvents(file string, launches []parse.RocketData, observedAt time.Time) {
+	observedAt = observedAt.UTC()
+	if h.Outcomes == nil {
+		h.Outcomes = map[string]map[string]parse.Outcome{}
+	}
+	outcomes, recorded := h.Outcomes[file]
+	if !recorded {
+		outcomes = map[string]parse.Outcome{}
+		h.Outcomes[file] = outcomes
+	}
+	seeding := !recorded
+
+	for _, launch := range launches {
+		if launch.Id == "" || launch.IsSpurious() {
+			continue
+		}
+		outcome := launch.Outcome()
+		previous, known := outcomes[launch.Id]
+		outcomes[launch.Id] = outcome
+		if seeding {
+			continue
+		}
+
+		if !known {
+			h.Events = append(h.Events, newEvent(EventAdded, observedAt, launch))
+		} else if outcome != previous {
+			event := newEvent(EventOutcome, observedAt, launch)
+			event.PreviousOutcome = previous
+			h.Events = append(h.Events, event)
+		}
+	}
+
+	// Only the feed reads events, so older ones would only make the history
+	// grow with every refresh
+	if len(h.Events) > feedLength {
+		h.Events = append([]Event(nil), h.Events[len(h.Events)-feedLength:]...)
+	}
+}
+
+// Latest is the most recent n events, newest first
+func (h History) Latest(n int) []Event {
+	var latest []Event
+	for i := len(h.Events) - 1; i >= 0 && len(latest) < n; i-- {
+		latest = append(latest, h.Events[i])
+	}
+	return latest
+}
@@ -0,0 +1,181 @@
+// Package history keeps track of how the scheduled time of upcoming launches
+// changes from one refresh to the next, which each cache run otherwise
+// overwrites. It also notes launches being added and their outcomes changing,
+// and publishes those as an Atom feed.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"launchdata/config"
+	"launchdata/jsonio"
+	"launchdata/parse"
+)
+
+const Filename = "history.json"
+
+// Observation is a scheduled time we saw for a launch during a refresh.
+// Timestamp is only meaningful when ParsedOk, otherwise TimestampClean holds
+// whatever wikipedia said, e.g. "Early 2023".
+type Observation struct {
+	ObservedAt     time.Time
+	TimestampClean string
+	Timestamp      time.Time
+	ParsedOk       bool
+	Tbd            bool
+}
+
+func newObservation(observedAt time.Time, t parse.TimeData) Observation {
+	return Observation{
+		ObservedAt:     observedAt,
+		TimestampClean: t.TimestampClean,
+		Timestamp:      t.Timestamp,
+		ParsedOk:       t.ParsedOk,
+		Tbd:            t.Tbd,
+	}
+}
+
+func (o Observation) sameTime(other Observation) bool {
+	if o.ParsedOk && other.ParsedOk {
+		return o.Timestamp.Equal(other.Timestamp)
+	}
+	return o.ParsedOk == other.ParsedOk && o.TimestampClean == other.TimestampClean
+}
+
+type Launch struct {
+	Id string
+	// File is the data file the launch is in, since ids are only unique
+	// within a file
+	File                  string `json:",omitempty"`
+	Rocket                string
+	LaunchServiceProvider string
+	LastSeen              time.Time
+
+	// Observations only has an entry when the scheduled time changed
+	Observations []Observation
+}
+
+type History struct {
+	// Launches are keyed by launchKey
+	Launches map[string]*Launch
+
+	// Outcomes is the last outcome seen of every launch, by file and then
+	// id, to tell when it changes. Files that aren't in it haven't been
+	// recorded yet.
+	Outcomes map[string]map[string]parse.Outcome `json:"OutcomesByFile,omitempty"`
+	// Events are the latest launches added and outcomes changed, oldest
+	// first. Only as many are kept as the feed shows.
+	Events []Event `json:",omitempty"`
+}
+
+func Path(dir string) string {
+	return filepath.Join(dir, Filename)
+}
+
+// Load reads the history in filename, returning an empty one if there isn't
+// one yet
+func Load(filename string) (History, error) {
+	h := History{Launches: map[string]*Launch{}}
+
+	f, err := os.Open(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	} else if err != nil {
+		return h, err
+	}
+	defer f.Close()
+
+	if err := json.NewDecoder(f).Decode(&h); err != nil {
+		return h, fmt.Errorf("reading %s: %w", filename, err)
+	}
+	if h.Launches == nil {
+		h.Launches = map[string]*Launch{}
+	}
+
+	return h, nil
+}
+
+func (h History) Write(config config.Config, filename string) error {
+	return jsonio.WriteJsonFile(config, h, filename)
+}
+
+// launchKey identifies a launch in file
+func launchKey(file string, id string) string {
+	return file + ":" + id
+}
+
+// Record adds what we saw of the launches in file at observedAt. Launches are
+// only tracked once they've been seen before they happened, since there's
+// nothing to learn about the schedule of a launch we first saw in the past.
+func (h *History) Record(file string, launches []parse.RocketData, observedAt time.Time) {
+	observedAt = observedAt.UTC()
+	for _, launch := range launches {
+		key := launchKey(file, launch.Id)
+		tracked, ok := h.Launches[key]
+		if legacy, isLegacy := h.Launches[launch.Id]; !ok && isLegacy && legacy.File == "" {
+			// Histories written before launches were keyed by file too
+			tracked, ok = legacy, true
+			tracked.File = file
+			delete(h.Launches, launch.Id)
+			h.Launches[key] = tracked
+		}
+		if !ok {
+			if launch.Timestamp.LaunchedAlready(observedAt) {
+				continue
+			}
+			tracked = &Launch{Id: launch.Id, File: file}
+			h.Launches[key] = tracked
+		}
+
+		tracked.Rocket = strings.TrimSpace(launch.Rocket)
+		tracked.LaunchServiceProvider = strings.TrimSpace(launch.LaunchServiceProvider)
+		tracked.LastSeen = observedAt
+
+		observation := newObservation(observedAt, launch.Timestamp)
+		n := len(tracked.Observations)
+		if n > 0 && tracked.Observations[n-1].sameTime(observation) {
+			continue
+		}
+		tracked.Observations = append(tracked.Observations, observation)
+	}
+}
+
+// Keys returns the keys of the tracked launches in a stable order
+func (h History) Keys() []string {
+	var keys []string
+	for key := range h.Launches {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Update records launches in the history next to filename, and rewrites the
+// feed of its latest events
+func Update(config config.Config, filename string, launches []parse.RocketData, observedAt time.Time) error {
+	if config.DryRun || filename == "" {
+		return nil
+	}
+
+	historyFile := Path(filepath.Dir(filename))
+	h, err := Load(historyFile)
+	if err != nil {
+		return err
+	}
+
+	file := filepath.Base(filename)
+	h.Record(file, launches, observedAt)
+	h.RecordEvents(file, launches, observedAt)
+
+	if err := h.Write(config, historyFile); err != nil {
+		return err
+	}
+	return h.writeFeedFile(FeedPath(filepath.Dir(filename)), observedAt)
+}
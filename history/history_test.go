@@ -0,0 +1,220 @@
+package history
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"launchdata/config"
+	"launchdata/parse"
+)
+
+func day(d int) time.Time {
+	return time.Date(2022, 1, d, 0, 0, 0, 0, time.UTC)
+}
+
+func scheduled(id string, provider string, t parse.TimeData) parse.RocketData {
+	return parse.RocketData{Id: id, Rocket: " Vulcan", LaunchServiceProvider: provider, Timestamp: t}
+}
+
+func precise(t time.Time) parse.TimeData {
+	return parse.TimeData{TimestampClean: t.Format("2 January"), Timestamp: t, ParsedOk: true}
+}
+
+func vague(s string) parse.TimeData {
+	return parse.TimeData{TimestampClean: s}
+}
+
+func TestRecordOnlyKeepsChanges(t *testing.T) {
+	h := History{Launches: map[string]*Launch{}}
+
+	h.Record("launchdata-2022.json", []parse.RocketData{
+		scheduled("upcoming", "ULA", precise(day(20))),
+		scheduled("historical", "ULA", precise(day(1))),
+	}, day(10))
+	h.Record("launchdata-2022.json", []parse.RocketData{scheduled("upcoming", "ULA", precise(day(20)))}, day(11))
+	h.Record("launchdata-2022.json", []parse.RocketData{scheduled("upcoming", "ULA", precise(day(25)))}, day(12))
+
+	assert.Equal(t, []string{"launchdata-2022.json:upcoming"}, h.Keys())
+	launch := h.Launches["launchdata-2022.json:upcoming"]
+	assert.Equal(t, "Vulcan", launch.Rocket)
+	assert.Equal(t, day(12), launch.LastSeen)
+	require.Len(t, launch.Observations, 2)
+	assert.Equal(t, day(25), launch.Observations[1].Timestamp)
+}
+
+func TestSlips(t *testing.T) {
+	h := History{Launches: map[string]*Launch{}}
+
+	h.Record("launchdata-2022.json", []parse.RocketData{scheduled("a", "ULA", precise(day(20))), scheduled("b", "SpaceX", precise(day(20)))}, day(1))
+	h.Record("launchdata-2022.json", []parse.RocketData{scheduled("a", "ULA", vague("TBD")), scheduled("b", "SpaceX", precise(day(19)))}, day(2))
+	h.Record("launchdata-2022.json", []parse.RocketData{scheduled("a", "ULA", precise(day(30))), scheduled("c", "ULA", precise(day(21)))}, day(3))
+
+	slips := h.Slips()
+	assert.Equal(t, []Slip{
+		{Id: "a", Rocket: "Vulcan", LaunchServiceProvider: "ULA", Scrubs: 1, DaysSlipped: 10},
+		{Id: "b", Rocket: "Vulcan", LaunchServiceProvider: "SpaceX", Scrubs: 0, DaysSlipped: -1},
+		{Id: "c", Rocket: "Vulcan", LaunchServiceProvider: "ULA", Scrubs: 0, DaysSlipped: 0},
+	}, slips)
+
+	assert.Equal(t, []GroupStats{
+		{Name: "ULA", Launches: 2, Scrubs: 1, MeanDaysSlipped: 5, MedianDaysSlipped: 5, MaxDaysSlipped: 10},
+		{Name: "SpaceX", Launches: 1, MeanDaysSlipped: -1, MedianDaysSlipped: -1, MaxDaysSlipped: -1},
+	}, ByProvider(slips))
+}
+
+func TestUpdateRoundTrips(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "launchdata-2022.json")
+	launches := []parse.RocketData{scheduled("a", "ULA", vague("Early 2022"))}
+
+	require.NoError(t, Update(config.Config{}, filename, launches, day(1)))
+	require.NoError(t, Update(config.Config{}, filename, launches, day(2)))
+
+	h, err := Load(Path(dir))
+	require.NoError(t, err)
+	launch := h.Launches["launchdata-2022.json:a"]
+	require.NotNil(t, launch)
+	assert.Len(t, launch.Observations, 1)
+	assert.Equal(t, day(2), launch.LastSeen)
+}
+
+func TestRecordKeepsFilesApart(t *testing.T) {
+	h := History{Launches: map[string]*Launch{}}
+
+	// Ids are only unique within a file
+	h.Record("launchdata-2022.json", []parse.RocketData{scheduled("a", "ULA", precise(day(20)))}, day(1))
+	h.Record("launchdata-2023.json", []parse.RocketData{scheduled("a", "SpaceX", vague("Q3"))}, day(1))
+	h.Record("launchdata-2022.json", []parse.RocketData{scheduled("a", "ULA", precise(day(20)))}, day(2))
+	h.Record("launchdata-2023.json", []parse.RocketData{scheduled("a", "SpaceX", vague("Q3"))}, day(2))
+
+	assert.Equal(t, []Slip{
+		{Id: "a", Rocket: "Vulcan", LaunchServiceProvider: "ULA"},
+		{Id: "a", Rocket: "Vulcan", LaunchServiceProvider: "SpaceX"},
+	}, h.Slips())
+	assert.Len(t, h.Launches["launchdata-2022.json:a"].Observations, 1)
+	assert.Len(t, h.Launches["launchdata-2023.json:a"].Observations, 1)
+}
+
+func TestRecordAdoptsLaunchesKeyedById(t *testing.T) {
+	h := History{Launches: map[string]*Launch{
+		"a": {Id: "a", Observations: []Observation{newObservation(day(1), precise(day(20)))}},
+	}}
+
+	h.Record("launchdata-2022.json", []parse.RocketData{scheduled("a", "ULA", precise(day(25)))}, day(2))
+
+	assert.Equal(t, []string{"launchdata-2022.json:a"}, h.Keys())
+	assert.Len(t, h.Launches["launchdata-2022.json:a"].Observations, 2)
+}
+
+func withOutcome(id string, outcome string) parse.RocketData {
+	launch := scheduled(id, "ULA", precise(day(20)))
+	launch.Payload = []parse.PayloadData{{Payload: "Peregrine", Outcome: outcome}}
+	return launch
+}
+
+func TestRecordEvents(t *testing.T) {
+	h := History{}
+
+	h.RecordEvents("launchdata-2022.json", []parse.RocketData{withOutcome("a", "Planned")}, day(1))
+	assert.Empty(t, h.Events, "the first refresh of a file only learns its launches")
+
+	h.RecordEvents("launchdata-2022.json", []parse.RocketData{
+		withOutcome("a", "Planned"),
+		withOutcome("b", "Planned"),
+		{Rocket: "March", Timestamp: parse.TimeData{TimestampRaw: "March"}},
+	}, day(2))
+	h.RecordEvents("launchdata-2022.json", []parse.RocketData{
+		withOutcome("a", "Successful"),
+		withOutcome("b", "Planned"),
+	}, day(3))
+	h.RecordEvents("launchdata-2023.json", []parse.RocketData{withOutcome("c", "Planned")}, day(3))
+
+	assert.Equal(t, []Event{
+		{Kind: EventAdded, ObservedAt: day(2), Id: "b", Timestamp: "2022-01-20T00:00:00Z", Rocket: "Vulcan", Payloads: []string{"Peregrine"}, Outcome: parse.OutcomePlanned},
+		{Kind: EventOutcome, ObservedAt: day(3), Id: "a", Timestamp: "2022-01-20T00:00:00Z", Rocket: "Vulcan", Payloads: []string{"Peregrine"}, Outcome: parse.OutcomeSuccess, PreviousOutcome: parse.OutcomePlanned},
+	}, h.Events)
+	assert.Equal(t, "a", h.Latest(1)[0].Id)
+}
+
+func TestRecordEventsKeepsFilesApart(t *testing.T) {
+	dir := t.TempDir()
+	failed := withOutcome("tsyklon-3/gonets-d1", "Launch failure")
+	succeeded := withOutcome("tsyklon-3/gonets-d1", "Successful")
+
+	// The same data recorded again shouldn't look like outcomes changing,
+	// even though both files have a launch with the same id
+	for d := 1; d <= 3; d++ {
+		require.NoError(t, Update(config.Config{}, filepath.Join(dir, "launchdata-2000s.json"), []parse.RocketData{failed}, day(d)))
+		require.NoError(t, Update(config.Config{}, filepath.Join(dir, "launchdata-2010s.json"), []parse.RocketData{succeeded}, day(d)))
+	}
+
+	h, err := Load(Path(dir))
+	require.NoError(t, err)
+	assert.Empty(t, h.Events)
+}
+
+func TestWriteFeed(t *testing.T) {
+	h := History{}
+	h.RecordEvents("launchdata-2022.json", nil, day(1))
+	h.RecordEvents("launchdata-2022.json", []parse.RocketData{withOutcome("vulcan/cert-1", "Planned")}, day(2))
+	h.RecordEvents("launchdata-2022.json", []parse.RocketData{withOutcome("vulcan/cert-1", "Successful")}, day(3))
+
+	var b bytes.Buffer
+	require.NoError(t, WriteFeed(&b, h.Latest(feedLength), day(4)))
+
+	var feed atomFeed
+	require.NoError(t, xml.Unmarshal(b.Bytes(), &feed))
+	assert.Equal(t, "2022-01-03T00:00:00Z", feed.Updated)
+	require.Len(t, feed.Entries, 2)
+	assert.Equal(t, atomEntry{
+		Id:       "urn:launchdata:launch:vulcan/cert-1:outcome:20220103T000000Z",
+		Title:    "Vulcan | Peregrine: planned → success",
+		Updated:  "2022-01-03T00:00:00Z",
+		Category: atomCategory{Term: "outcome"},
+		Content: atomContent{Type: "text", Text: "Launch time: 2022-01-20T00:00:00Z\n" +
+			"Vehicle: Vulcan\nPayloads: Peregrine\nOutcome: success, was planned\nId: vulcan/cert-1"},
+	}, feed.Entries[0])
+	assert.Equal(t, "urn:launchdata:launch:vulcan/cert-1:added", feed.Entries[1].Id)
+	assert.Equal(t, "New launch: Vulcan | Peregrine", feed.Entries[1].Title)
+
+	b.Reset()
+	require.NoError(t, WriteFeed(&b, nil, day(4)))
+	assert.Contains(t, b.String(), "<updated>2022-01-04T00:00:00Z</updated>")
+}
+
+func TestUpdateWritesFeed(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "launchdata-2022.json")
+
+	require.NoError(t, Update(config.Config{}, filename, []parse.RocketData{withOutcome("a", "Planned")}, day(1)))
+	require.NoError(t, Update(config.Config{}, filename, []parse.RocketData{withOutcome("a", "Launch failure")}, day(2)))
+
+	h, err := Load(Path(dir))
+	require.NoError(t, err)
+	require.Len(t, h.Events, 1)
+	assert.Equal(t, parse.OutcomeLaunchFailure, h.Events[0].Outcome)
+
+	feed, err := os.ReadFile(FeedPath(dir))
+	require.NoError(t, err)
+	assert.Contains(t, string(feed), "<title>Vulcan | Peregrine: planned → launch failure</title>")
+}
+
+func TestRecordEventsKeepsTheLatest(t *testing.T) {
+	h := History{}
+	h.RecordEvents("launchdata-2022.json", nil, day(1))
+	for i := 0; i < feedLength+5; i++ {
+		h.RecordEvents("launchdata-2022.json", []parse.RocketData{withOutcome(fmt.Sprintf("launch-%d", i), "Planned")}, day(2))
+	}
+
+	require.Len(t, h.Events, feedLength)
+	assert.Equal(t, "launch-5", h.Events[0].Id)
+	assert.Equal(t, fmt.Sprintf("launch-%d", feedLength+4), h.Events[feedLength-1].Id)
+}
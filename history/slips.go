@@ -0,0 +1,125 @@
+package history
+
+import (
+	"sort"
+)
+
+// Slip summarises how much a launch's schedule moved while we were watching it
+type Slip struct {
+	Id                    string
+	Rocket                string
+	LaunchServiceProvider string
+
+	// Scrubs is the number of times the launch moved later, including being
+	// pushed back to a vaguer time like "Q3" or "TBD"
+	Scrubs int
+
+	// DaysSlipped is the distance between the first and last times we could
+	// parse, negative if the launch was brought forward
+	DaysSlipped float64
+}
+
+func (o Observation) movedLaterThan(previous Observation) bool {
+	switch {
+	case o.ParsedOk && previous.ParsedOk:
+		return o.Timestamp.After(previous.Timestamp)
+	case previous.ParsedOk && !o.ParsedOk:
+		// a precise time was replaced by a vague one
+		return true
+	}
+	return false
+}
+
+func (l Launch) Slip() Slip {
+	slip := Slip{
+		Id:                    l.Id,
+		Rocket:                l.Rocket,
+		LaunchServiceProvider: l.LaunchServiceProvider,
+	}
+
+	var first, last *Observation
+	for i := range l.Observations {
+		observation := &l.Observations[i]
+		if i > 0 && observation.movedLaterThan(l.Observations[i-1]) {
+			slip.Scrubs++
+		}
+		if observation.ParsedOk {
+			if first == nil {
+				first = observation
+			}
+			last = observation
+		}
+	}
+
+	if first != nil {
+		slip.DaysSlipped = last.Timestamp.Sub(first.Timestamp).Hours() / 24
+	}
+
+	return slip
+}
+
+func (h History) Slips() []Slip {
+	var slips []Slip
+	for _, key := range h.Keys() {
+		slips = append(slips, h.Launches[key].Slip())
+	}
+	return slips
+}
+
+// GroupStats are the slip statistics for all the launches by one provider
+// or of one vehicle
+type GroupStats struct {
+	Name              string
+	Launches          int
+	Scrubs            int
+	MeanDaysSlipped   float64
+	MedianDaysSlipped float64
+	MaxDaysSlipped    float64
+}
+
+func groupBy(slips []Slip, key func(Slip) string) []GroupStats {
+	groups := map[string][]Slip{}
+	for _, slip := range slips {
+		groups[key(slip)] = append(groups[key(slip)], slip)
+	}
+
+	var stats []GroupStats
+	for name, group := range groups {
+		var days []float64
+		s := GroupStats{Name: name, Launches: len(group)}
+		for _, slip := range group {
+			s.Scrubs += slip.Scrubs
+			s.MeanDaysSlipped += slip.DaysSlipped
+			days = append(days, slip.DaysSlipped)
+		}
+		s.MeanDaysSlipped /= float64(len(group))
+
+		sort.Float64s(days)
+		s.MaxDaysSlipped = days[len(days)-1]
+		if len(days)%2 == 1 {
+			s.MedianDaysSlipped = days[len(days)/2]
+		} else {
+			s.MedianDaysSlipped = (days[len(days)/2-1] + days[len(days)/2]) / 2
+		}
+
+		stats = append(stats, s)
+	}
+
+	// Worst offenders first
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].MeanDaysSlipped != stats[j].MeanDaysSlipped {
+			return stats[i].MeanDaysSlipped > stats[j].MeanDaysSlipped
+		}
+		return stats[i].Name < stats[j].Name
+	})
+
+	return stats
+}
+
+func ByProvider(slips []Slip) []GroupStats {
+	return groupBy(slips, func(s Slip) string { return s.LaunchServiceProvider })
+}
+
+func ByVehicle(slips []Slip) []GroupStats {
+	return groupBy(slips, func(s Slip) string { return s.Rocket })
+}
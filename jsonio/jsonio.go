@@ -19,6 +19,11 @@ func newRawResponse(r io.Reader) (RawResponse, error) {
 	return res, json.NewDecoder(r).Decode(&res)
 }
 
+// Decode parses the body of a wikitable2json response
+func Decode(body []byte) (RawResponse, error) {
+	return newRawResponse(bytes.NewReader(body))
+}
+
 func LoadFromFile(filename string) (RawResponse, error) {
 	jsonfile, err := os.Open(filename)
 	if err != nil {
@@ -38,22 +43,44 @@ func Get(config config.Config, url string) (RawResponse, error) {
 		return response, nil
 	}
 
-	resp, err := http.Get(url)
+	body, err := GetRaw(config, url)
 	if err != nil {
 		return nil, err
 	}
 
-	response, err = newRawResponse(resp.Body)
+	response, err = Decode(body)
 	if err != nil {
 		log.Printf("URL: %#v", url)
-		log.Printf("Response: \n %#v", resp.Body)
+		log.Printf("Response: \n %s", body)
+	}
+
+	return response, err
+}
+
+// GetRaw returns the undecoded body of a request, so callers can hold on to
+// exactly what the server sent
+func GetRaw(config config.Config, url string) ([]byte, error) {
+	if config.DryRun {
+		fmt.Printf("Dry run: Would request %s\n", url)
+		return nil, nil
 	}
 
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+
 	if err := resp.Body.Close(); err != nil {
 		log.Printf("Error closing http body: %v", err)
 	}
 
-	return response, err
+	if err == nil && resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("unexpected status %s for %s", resp.Status, url)
+	}
+
+	return body, err
 }
 
 func FormattedJson(contents interface{}) (*bytes.Buffer, error) {
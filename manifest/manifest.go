@@ -0,0 +1,169 @@
+// Package manifest records where each cached data file came from, so a file
+// can be traced back to the pages, revisions and parser that produced it and
+// checked for tampering or corruption later.
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"launchdata/config"
+	"launchdata/jsonio"
+	"launchdata/parse"
+)
+
+const Filename = "manifest.json"
+
+type File struct {
+	Sources           []parse.Source
+	FetchedAt         time.Time
+	Sha256            string
+	OrbitalFlights    int
+	SuborbitalFlights int
+	Diagnostics       int
+	ParserVersion     int
+}
+
+// Manifest describes the data files in a directory, keyed by their name
+// relative to that directory
+type Manifest struct {
+	Files map[string]File
+}
+
+func Path(dir string) string {
+	return filepath.Join(dir, Filename)
+}
+
+// Load reads the manifest in dir, returning an empty one if there isn't one yet
+func Load(dir string) (Manifest, error) {
+	m := Manifest{Files: map[string]File{}}
+
+	f, err := os.Open(Path(dir))
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	} else if err != nil {
+		return m, err
+	}
+	defer f.Close()
+
+	if err := json.NewDecoder(f).Decode(&m); err != nil {
+		return m, fmt.Errorf("reading %s: %w", Path(dir), err)
+	}
+	if m.Files == nil {
+		m.Files = map[string]File{}
+	}
+
+	return m, nil
+}
+
+func (m Manifest) Write(config config.Config, dir string) error {
+	return jsonio.WriteJsonFile(config, m, Path(dir))
+}
+
+// Record adds an entry for filename, which must already have been written
+// from result
+func (m *Manifest) Record(filename string, result parse.Result) error {
+	sum, err := fileSha256(filename)
+	if err != nil {
+		return err
+	}
+
+	var fetchedAt time.Time
+	for _, source := range result.Sources {
+		if source.FetchedAt.After(fetchedAt) {
+			fetchedAt = source.FetchedAt
+		}
+	}
+
+	m.Files[filepath.Base(filename)] = File{
+		Sources:           result.Sources,
+		FetchedAt:         fetchedAt,
+		Sha256:            sum,
+		OrbitalFlights:    len(result.Data.OrbitalFlights),
+		SuborbitalFlights: len(result.Data.SuborbitalFlights),
+		Diagnostics:       len(result.Diagnostics),
+		ParserVersion:     parse.ParserVersion,
+	}
+
+	return nil
+}
+
+// Names returns the files in the manifest in a stable order
+func (m Manifest) Names() []string {
+	var names []string
+	for name := range m.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Update records filename in the manifest next to it
+func Update(config config.Config, filename string, result parse.Result) error {
+	if config.DryRun || filename == "" {
+		return nil
+	}
+
+	dir := filepath.Dir(filename)
+	m, err := Load(dir)
+	if err != nil {
+		return err
+	}
+
+	if err := m.Record(filename, result); err != nil {
+		return err
+	}
+
+	return m.Write(config, dir)
+}
+
+func fileSha256(filename string) (string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Rehash updates the checksum of a file that was rewritten without being
+// fetched again, e.g. by migrating it to a new schema. Files that aren't in the
+// manifest are left out of it.
+func Rehash(config config.Config, filename string) error {
+	if config.DryRun {
+		return nil
+	}
+
+	dir := filepath.Dir(filename)
+	m, err := Load(dir)
+	if err != nil {
+		return err
+	}
+
+	entry, ok := m.Files[filepath.Base(filename)]
+	if !ok {
+		return nil
+	}
+
+	entry.Sha256, err = fileSha256(filename)
+	if err != nil {
+		return err
+	}
+	m.Files[filepath.Base(filename)] = entry
+
+	return m.Write(config, dir)
+}
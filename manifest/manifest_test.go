@@ -0,0 +1,73 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"launchdata/config"
+	"launchdata/jsonio"
+	"launchdata/parse"
+)
+
+func writeDataFile(t *testing.T, dir string, name string, data parse.AllLaunchData) parse.Result {
+	t.Helper()
+	require.NoError(t, jsonio.WriteJsonFile(config.Config{}, data, filepath.Join(dir, name)))
+
+	result := parse.Result{
+		Data: data,
+		Sources: []parse.Source{{
+			Url:        "https://www.wikitable2json.com/api/2022_in_spaceflight",
+			WikiUrl:    "https://en.wikipedia.org/wiki/2022_in_spaceflight",
+			RevisionId: 1234,
+			FetchedAt:  time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+	require.NoError(t, Update(config.Config{}, filepath.Join(dir, name), result))
+	return result
+}
+
+func TestUpdateRecordsFile(t *testing.T) {
+	dir := t.TempDir()
+	data := parse.AllLaunchData{OrbitalFlights: []parse.RocketData{{Rocket: "Falcon 9"}, {Rocket: "Electron"}}}
+	result := writeDataFile(t, dir, "launchdata-2022.json", data)
+
+	m, err := Load(dir)
+	require.NoError(t, err)
+
+	entry := m.Files["launchdata-2022.json"]
+	assert.Equal(t, 2, entry.OrbitalFlights)
+	assert.Equal(t, result.Sources, entry.Sources)
+	assert.Equal(t, result.Sources[0].FetchedAt, entry.FetchedAt)
+	assert.Equal(t, parse.ParserVersion, entry.ParserVersion)
+	assert.Len(t, entry.Sha256, 64)
+}
+
+func TestVerify(t *testing.T) {
+	dir := t.TempDir()
+	data := parse.AllLaunchData{OrbitalFlights: []parse.RocketData{{Rocket: "Falcon 9"}}}
+	writeDataFile(t, dir, "launchdata-2021.json", data)
+	writeDataFile(t, dir, "launchdata-2022.json", data)
+
+	problems, err := Verify(dir)
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+
+	// Tamper with one file, remove another and add one the manifest doesn't know about
+	require.NoError(t, jsonio.WriteJsonFile(config.Config{}, parse.AllLaunchData{}, filepath.Join(dir, "launchdata-2022.json")))
+	require.NoError(t, os.Remove(filepath.Join(dir, "launchdata-2021.json")))
+	require.NoError(t, jsonio.WriteJsonFile(config.Config{}, data, filepath.Join(dir, "launchdata-2020.json")))
+
+	problems, err = Verify(dir)
+	require.NoError(t, err)
+
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.Filename)
+	}
+	assert.ElementsMatch(t, []string{"launchdata-2021.json", "launchdata-2022.json", "launchdata-2022.json", "launchdata-2020.json"}, got)
+}
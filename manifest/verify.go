@@ -0,0 +1,79 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"launchdata/parse"
+)
+
+// Problem is a way in which a data file doesn't match its manifest entry
+type Problem struct {
+	Filename string
+	Message  string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", p.Filename, p.Message)
+}
+
+// Verify checks every file listed in the manifest in dir, and looks for data
+// files that aren't listed at all
+func Verify(dir string) ([]Problem, error) {
+	m, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(Path(dir)); errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no %s in %s", Filename, dir)
+	}
+
+	var problems []Problem
+	for _, name := range m.Names() {
+		problems = append(problems, verifyFile(filepath.Join(dir, name), m.Files[name])...)
+	}
+
+	unlisted, err := filepath.Glob(filepath.Join(dir, "launchdata-*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, filename := range unlisted {
+		name := filepath.Base(filename)
+		if _, ok := m.Files[name]; !ok {
+			problems = append(problems, Problem{name, "not listed in the manifest"})
+		}
+	}
+
+	return problems, nil
+}
+
+func verifyFile(filename string, entry File) []Problem {
+	name := filepath.Base(filename)
+
+	sum, err := fileSha256(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Problem{{name, "listed in the manifest but missing"}}
+	} else if err != nil {
+		return []Problem{{name, err.Error()}}
+	}
+
+	var problems []Problem
+	if sum != entry.Sha256 {
+		problems = append(problems, Problem{name, fmt.Sprintf("sha256 is %s, manifest says %s", sum, entry.Sha256)})
+	}
+
+	data, err := parse.LoadLaunchDataFromFile(filename)
+	if err != nil {
+		return append(problems, Problem{name, err.Error()})
+	}
+	if got := len(data.OrbitalFlights); got != entry.OrbitalFlights {
+		problems = append(problems, Problem{name, fmt.Sprintf("has %d orbital flights, manifest says %d", got, entry.OrbitalFlights)})
+	}
+	if got := len(data.SuborbitalFlights); got != entry.SuborbitalFlights {
+		problems = append(problems, Problem{name, fmt.Sprintf("has %d suborbital flights, manifest says %d", got, entry.SuborbitalFlights)})
+	}
+
+	return problems
+}
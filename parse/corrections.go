@@ -0,0 +1,322 @@
+package parse
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Correction records a patch that was applied to a launch, so a corrected
+// value can be told apart from one that came from wikipedia
+type Correction struct {
+	Patch  string
+	Author string
+	Reason string
+	Date   string
+}
+
+// Patch is a manual correction to a single launch, kept in its own file in
+// the corrections directory so it survives the launch being fetched again.
+//
+// Fields are named as they are in RocketData, and payloads by their name,
+// e.g. "LaunchSite" or "Payload[Starlink Group 4-5].Outcome". Removing
+// "Payload[name]" removes the whole payload.
+type Patch struct {
+	// Name is the file the patch was read from, without its extension
+	Name string `json:"-"`
+
+	Year     int
+	LaunchId string
+	Author   string
+	Reason   string
+	// Date is when the patch was written, as 2006-01-02
+	Date string
+
+	Set         map[string]string `json:",omitempty"`
+	Remove      []string          `json:",omitempty"`
+	AddPayloads []PayloadData     `json:",omitempty"`
+}
+
+func (p Patch) validate() error {
+	var missing []string
+	if p.Year == 0 {
+		missing = append(missing, "Year")
+	}
+	for name, value := range map[string]string{"LaunchId": p.LaunchId, "Author": p.Author, "Reason": p.Reason, "Date": p.Date} {
+		if strings.TrimSpace(value) == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("missing %s", strings.Join(missing, ", "))
+	}
+
+	if _, err := time.Parse("2006-01-02", p.Date); err != nil {
+		return fmt.Errorf("Date %q is not 2006-01-02", p.Date)
+	}
+	return nil
+}
+
+// LoadCorrections reads every patch in dir. A missing directory just means
+// there are no corrections.
+func LoadCorrections(dir string) ([]Patch, error) {
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	filenames, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(filenames)
+
+	var patches []Patch
+	for _, filename := range filenames {
+		contents, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(contents))
+		decoder.DisallowUnknownFields()
+		var patch Patch
+		if err := decoder.Decode(&patch); err != nil {
+			return nil, fmt.Errorf("reading correction %s: %w", filename, err)
+		}
+		if err := patch.validate(); err != nil {
+			return nil, fmt.Errorf("correction %s: %w", filename, err)
+		}
+		patch.Name = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+		patches = append(patches, patch)
+	}
+	return patches, nil
+}
+
+// PatchError is a patch that matched a launch but couldn't be applied to it
+type PatchError struct {
+	Patch string
+	Year  int
+	Err   string
+}
+
+type CorrectionReport struct {
+	Applied []string
+	// Stale patches are for a year we have, but no longer match any launch in
+	// it, usually because the launch's id changed
+	Stale  []Patch
+	Failed []PatchError
+}
+
+// Diagnostics describes the patches that weren't applied
+func (r CorrectionReport) Diagnostics() []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, patch := range r.Stale {
+		diagnostics = append(diagnostics, Diagnostic{Year: patch.Year, Message: fmt.Sprintf("correction %s doesn't match any launch, no launch has id %q", patch.Name, patch.LaunchId)})
+	}
+	for _, failed := range r.Failed {
+		diagnostics = append(diagnostics, Diagnostic{Year: failed.Year, Message: fmt.Sprintf("correction %s couldn't be applied: %s", failed.Patch, failed.Err)})
+	}
+	return diagnostics
+}
+
+// ApplyCorrections applies each patch to the launch with its id among the
+// launches from the patch's year. launches has every year that's being
+// corrected, each with one or more lists, e.g. a file's orbital and
+// suborbital flights. A patch is applied completely or not at all, and
+// applying it again does nothing more.
+func ApplyCorrections(patches []Patch, launches map[int][][]RocketData) CorrectionReport {
+	// Ids are only unique within a year. The earlier list wins if two share
+	// one.
+	byId := map[int]map[string]*RocketData{}
+	for year, lists := range launches {
+		byId[year] = map[string]*RocketData{}
+		for l := len(lists) - 1; l >= 0; l-- {
+			for i := range lists[l] {
+				byId[year][lists[l][i].Id] = &lists[l][i]
+			}
+		}
+	}
+
+	var report CorrectionReport
+	for _, patch := range patches {
+		inYear, ok := byId[patch.Year]
+		if !ok {
+			continue
+		}
+
+		launch, ok := inYear[patch.LaunchId]
+		if !ok {
+			report.Stale = append(report.Stale, patch)
+			continue
+		}
+
+		corrected, err := patch.apply(*launch)
+		if err != nil {
+			report.Failed = append(report.Failed, PatchError{Patch: patch.Name, Year: patch.Year, Err: err.Error()})
+			continue
+		}
+		*launch = corrected
+		report.Applied = append(report.Applied, patch.Name)
+	}
+	return report
+}
+
+var payloadFieldRegex = regexp.MustCompile(`^Payload\[(.+)\](?:\.(\w+))?$`)
+
+// copyLaunch copies a launch deeply enough that a failed patch can't leave
+// half its changes behind
+func copyLaunch(launch RocketData) RocketData {
+	launch.Payload = append([]PayloadData(nil), launch.Payload...)
+	launch.Boosters = append([]BoosterData(nil), launch.Boosters...)
+	launch.Corrections = append([]Correction(nil), launch.Corrections...)
+	return launch
+}
+
+func (p Patch) apply(launch RocketData) (RocketData, error) {
+	launch = copyLaunch(launch)
+
+	for _, payload := range p.AddPayloads {
+		if _, ok := findPayload(launch, payload.Payload); !ok {
+			launch.Payload = append(launch.Payload, payload)
+		}
+	}
+
+	// Sort the fields so errors come out the same every time
+	var fields []string
+	for field := range p.Set {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		if err := setField(&launch, field, p.Set[field]); err != nil {
+			return launch, err
+		}
+	}
+
+	for _, field := range p.Remove {
+		if err := removeField(&launch, field); err != nil {
+			return launch, err
+		}
+	}
+
+	correction := Correction{Patch: p.Name, Author: p.Author, Reason: p.Reason, Date: p.Date}
+	for _, applied := range launch.Corrections {
+		if applied.Patch == p.Name {
+			return launch, nil
+		}
+	}
+	launch.Corrections = append(launch.Corrections, correction)
+	return launch, nil
+}
+
+func findPayload(launch RocketData, name string) (int, bool) {
+	for i, payload := range launch.Payload {
+		if strings.TrimSpace(payload.Payload) == strings.TrimSpace(name) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// resolveField finds the struct field a patch refers to
+func resolveField(launch *RocketData, field string) (reflect.Value, error) {
+	target := reflect.ValueOf(launch).Elem()
+	name := field
+
+	if match := payloadFieldRegex.FindStringSubmatch(field); match != nil {
+		i, ok := findPayload(*launch, match[1])
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("no payload named %q", match[1])
+		}
+		if match[2] == "" {
+			return reflect.Value{}, fmt.Errorf("%s is a whole payload, only its fields can be set", field)
+		}
+		target = reflect.ValueOf(&launch.Payload[i]).Elem()
+		name = match[2]
+	}
+
+	value := target.FieldByName(name)
+	if !value.IsValid() || name == "Id" || name == "Corrections" {
+		return reflect.Value{}, fmt.Errorf("unknown field %q", field)
+	}
+	return value, nil
+}
+
+func setField(launch *RocketData, field string, value string) error {
+	if field == "Timestamp" {
+		t, err := parseCorrectedTimestamp(value)
+		if err != nil {
+			return err
+		}
+		launch.Timestamp = t
+		return nil
+	}
+
+	target, err := resolveField(launch, field)
+	if err != nil {
+		return err
+	}
+
+	switch target.Kind() {
+	case reflect.String:
+		target.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s needs true or false, not %q", field, value)
+		}
+		target.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s needs a whole number, not %q", field, value)
+		}
+		target.SetInt(n)
+	default:
+		return fmt.Errorf("%s can't be set, only removed", field)
+	}
+	return nil
+}
+
+func removeField(launch *RocketData, field string) error {
+	if match := payloadFieldRegex.FindStringSubmatch(field); match != nil && match[2] == "" {
+		// It's fine if it's gone already, e.g. when the patch is applied again
+		if i, ok := findPayload(*launch, match[1]); ok {
+			launch.Payload = append(launch.Payload[:i], launch.Payload[i+1:]...)
+		}
+		return nil
+	}
+
+	target, err := resolveField(launch, field)
+	if err != nil {
+		return err
+	}
+	target.Set(reflect.Zero(target.Type()))
+	return nil
+}
+
+// parseCorrectedTimestamp reads a timestamp written by hand in a patch, which
+// unlike wikipedia's always has the year
+func parseCorrectedTimestamp(value string) (TimeData, error) {
+	formats := []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"}
+	for _, format := range formats {
+		if t, err := time.Parse(format, value); err == nil {
+			return TimeData{
+				TimestampRaw:   value,
+				TimestampClean: value,
+				Timestamp:      t.UTC(),
+				ParsedOk:       true,
+			}, nil
+		}
+	}
+	return TimeData{}, fmt.Errorf("Timestamp %q is not 2006-01-02 15:04 or RFC 3339", value)
+}
@@ -0,0 +1,137 @@
+package parse
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCorrection(t *testing.T, dir string, name string, contents string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644))
+}
+
+func TestLoadCorrections(t *testing.T) {
+	patches, err := LoadCorrections(filepath.Join(t.TempDir(), "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, patches)
+
+	dir := t.TempDir()
+	writeCorrection(t, dir, "wrong-pad.json", `{
+		"Year": 2022, "LaunchId": "falcon-9/138", "Author": "Jane", "Reason": "Wrong pad", "Date": "2023-02-01",
+		"Set": {"LaunchSite": "Vandenberg SLC-4E"}
+	}`)
+	patches, err = LoadCorrections(dir)
+	require.NoError(t, err)
+	require.Len(t, patches, 1)
+	assert.Equal(t, "wrong-pad", patches[0].Name)
+	assert.Equal(t, "Vandenberg SLC-4E", patches[0].Set["LaunchSite"])
+
+	writeCorrection(t, dir, "anonymous.json", `{"Year": 2022, "LaunchId": "falcon-9/138", "Date": "2023-02-01"}`)
+	_, err = LoadCorrections(dir)
+	assert.ErrorContains(t, err, "missing Author, Reason")
+}
+
+func TestApplyCorrections(t *testing.T) {
+	launches := []RocketData{
+		{
+			Id:         "falcon-9/138",
+			Rocket:     "Falcon 9",
+			LaunchSite: "Cape Canaveral SLC-40",
+			Notes:      "Wrong notes",
+			Payload: []PayloadData{
+				{Payload: "NROL-87", Outcome: "Unknown"},
+				{Payload: "February", Outcome: "February"},
+			},
+		},
+		{Id: "electron/25"},
+	}
+
+	patch := Patch{
+		Name: "nrol-87", Year: 2022, LaunchId: "falcon-9/138", Author: "Jane", Reason: "Press kit", Date: "2023-02-01",
+		Set: map[string]string{
+			"LaunchSite":               "Vandenberg SLC-4E",
+			"Payload[NROL-87].Outcome": "Successful",
+			"Timestamp":                "2022-02-02 20:18",
+		},
+		Remove:      []string{"Notes", "Payload[February]"},
+		AddPayloads: []PayloadData{{Payload: "Rideshare", Cubesat: true}},
+	}
+	patches := []Patch{
+		patch,
+		{Name: "gone", Year: 2022, LaunchId: "falcon-9/139"},
+		{Name: "other-year", Year: 2021, LaunchId: "falcon-9/100"},
+		{Name: "typo", Year: 2022, LaunchId: "electron/25", Set: map[string]string{"LaunchSight": "Mahia"}},
+	}
+
+	report := ApplyCorrections(patches, map[int][][]RocketData{2022: {launches}})
+	assert.Equal(t, []string{"nrol-87"}, report.Applied)
+	require.Len(t, report.Stale, 1)
+	assert.Equal(t, "gone", report.Stale[0].Name)
+	assert.Equal(t, []PatchError{{Patch: "typo", Year: 2022, Err: `unknown field "LaunchSight"`}}, report.Failed)
+	assert.Len(t, report.Diagnostics(), 2)
+
+	corrected := launches[0]
+	assert.Equal(t, "Vandenberg SLC-4E", corrected.LaunchSite)
+	assert.Empty(t, corrected.Notes)
+	assert.Equal(t, time.Date(2022, 2, 2, 20, 18, 0, 0, time.UTC), corrected.Timestamp.Timestamp)
+	assert.Equal(t, []PayloadData{
+		{Payload: "NROL-87", Outcome: "Successful"},
+		{Payload: "Rideshare", Cubesat: true},
+	}, corrected.Payload)
+	assert.Equal(t, []Correction{{Patch: "nrol-87", Author: "Jane", Reason: "Press kit", Date: "2023-02-01"}}, corrected.Corrections)
+
+	// Applying it again changes nothing
+	ApplyCorrections([]Patch{patch}, map[int][][]RocketData{2022: {launches}})
+	assert.Equal(t, corrected, launches[0])
+
+	// A patch that fails part way through doesn't change anything
+	broken := patch
+	broken.Name = "broken"
+	broken.Set = map[string]string{"LaunchServiceProvider": "SpaceX", "Payload[Missing].Outcome": "Successful"}
+	report = ApplyCorrections([]Patch{broken}, map[int][][]RocketData{2022: {launches}})
+	assert.Len(t, report.Failed, 1)
+	assert.Equal(t, corrected, launches[0])
+}
+
+func TestApplyCorrectionsToSuborbitalFlights(t *testing.T) {
+	orbital := []RocketData{{Id: "falcon-9/138"}}
+	suborbital := []RocketData{{Id: "new-shepard/ns-22", LaunchSite: "Corn Ranch"}}
+	patch := Patch{
+		Name: "ns-22", Year: 2022, LaunchId: "new-shepard/ns-22", Author: "Jane", Reason: "Pad name", Date: "2023-02-01",
+		Set: map[string]string{"LaunchSite": "Corn Ranch LS-1"},
+	}
+
+	report := ApplyCorrections([]Patch{patch}, map[int][][]RocketData{2022: {orbital, suborbital}})
+	assert.Equal(t, []string{"ns-22"}, report.Applied)
+	assert.Empty(t, report.Stale)
+	assert.Equal(t, "Corn Ranch LS-1", suborbital[0].LaunchSite)
+	assert.Equal(t, RocketData{Id: "falcon-9/138"}, orbital[0])
+}
+
+func TestApplyCorrectionsOnlyToThePatchYear(t *testing.T) {
+	// Ids are only unique within a year
+	launches := map[int][][]RocketData{
+		1951: {{{Id: "r-1/29-january", Notes: "1951"}}},
+		1955: {{{Id: "r-1/29-january", Notes: "1955"}}},
+	}
+	patch := Patch{
+		Name: "r-1", Year: 1951, LaunchId: "r-1/29-january", Author: "Jane", Reason: "Archive", Date: "2023-02-01",
+		Set: map[string]string{"LaunchSite": "Kapustin Yar"},
+	}
+
+	report := ApplyCorrections([]Patch{patch}, launches)
+	assert.Equal(t, []string{"r-1"}, report.Applied)
+	assert.Equal(t, "Kapustin Yar", launches[1951][0][0].LaunchSite)
+	assert.Empty(t, launches[1955][0][0].LaunchSite)
+
+	// or stale if the year doesn't have it, even if another year does
+	patch.Year = 1952
+	launches[1952] = [][]RocketData{{{Id: "r-1/13-july"}}}
+	report = ApplyCorrections([]Patch{patch}, launches)
+	assert.Len(t, report.Stale, 1)
+}
@@ -0,0 +1,15 @@
+package parse
+
+import "fmt"
+
+// Diagnostic is a problem noticed while parsing that didn't stop us from
+// producing data, but probably means some of it is missing or wrong
+type Diagnostic struct {
+	Year    int
+	Url     string
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%d (%s): %s", d.Year, d.Url, d.Message)
+}
@@ -0,0 +1,140 @@
+package parse
+
+import (
+	"strings"
+)
+
+// Rendezvous is a row of the "Deep-space rendezvous" table on a year page: a
+// flyby, orbit insertion, landing or the like
+type Rendezvous struct {
+	Date       TimeData
+	Spacecraft string
+	Event      string
+	Remarks    string `json:",omitempty"`
+}
+
+// Eva is a row of the "Extravehicular activities" table on a year page
+type Eva struct {
+	Start      TimeData
+	Duration   string
+	End        string `json:",omitempty"`
+	Spacecraft string
+	Crew       []string
+	Remarks    string `json:",omitempty"`
+}
+
+func hasHeader(header []string, name string) bool {
+	return columnIndex(header, name) >= 0
+}
+
+func isRendezvousTable(header []string) bool {
+	return len(header) > 0 &&
+		strings.HasPrefix(normaliseHeader(header[0]), "date") &&
+		hasHeader(header, "spacecraft") && hasHeader(header, "event")
+}
+
+func isEvaTable(header []string) bool {
+	return len(header) > 0 &&
+		strings.HasPrefix(normaliseHeader(header[0]), "start") &&
+		hasHeader(header, "duration") && hasHeader(header, "crew")
+}
+
+// isSpanningRow spots rows with a single cell stretched across the table, like
+// month headings, which wikitable2json repeats in every column
+func isSpanningRow(row []string) bool {
+	if len(row) < 2 {
+		return true
+	}
+	for _, cell := range row[1:] {
+		if cell != row[0] {
+			return false
+		}
+	}
+	return true
+}
+
+func parseRendezvous(table [][]string, year int) []Rendezvous {
+	header := table[0]
+	date := 0
+	spacecraft := columnIndex(header, "spacecraft")
+	event := columnIndex(header, "event")
+	remarks := columnIndex(header, "remarks")
+
+	var rendezvous []Rendezvous
+	for _, row := range table[1:] {
+		if isSpanningRow(row) {
+			continue
+		}
+		rendezvous = append(rendezvous, Rendezvous{
+			Date:       parseTimestamp(strings.TrimSpace(row[date]), year),
+			Spacecraft: cellString(row, spacecraft),
+			Event:      cellString(row, event),
+			Remarks:    cellString(row, remarks),
+		})
+	}
+	return rendezvous
+}
+
+// splitCrew splits a crew cell, which has one astronaut per line
+func splitCrew(cell string) []string {
+	var crew []string
+	for _, name := range strings.Split(cell, "\n") {
+		if name = strings.TrimSpace(name); name != "" {
+			crew = append(crew, name)
+		}
+	}
+	return crew
+}
+
+func parseEvas(table [][]string, year int) []Eva {
+	header := table[0]
+	start := 0
+	duration := columnIndex(header, "duration")
+	end := columnIndex(header, "end time", "end")
+	spacecraft := columnIndex(header, "spacecraft")
+	crew := columnIndex(header, "crew")
+	remarks := columnIndex(header, "remarks")
+
+	var evas []Eva
+	for _, row := range table[1:] {
+		if isSpanningRow(row) {
+			continue
+		}
+		evas = append(evas, Eva{
+			Start:      parseTimestamp(strings.TrimSpace(row[start]), year),
+			Duration:   cellString(row, duration),
+			End:        cellString(row, end),
+			Spacecraft: cellString(row, spacecraft),
+			Crew:       splitCrew(cellString(row, crew)),
+			Remarks:    cellString(row, remarks),
+		})
+	}
+	return evas
+}
+
+// yearTables are the tables on a "YYYY in spaceflight" page other than the
+// launches themselves
+type yearTables struct {
+	statistics Statistics
+	rendezvous []Rendezvous
+	evas       []Eva
+}
+
+// parseYearTables picks the tables we understand out of all the tables on a
+// year page, by their headers
+func parseYearTables(tables [][][]string, year int) yearTables {
+	parsed := yearTables{statistics: parseStatistics(tables, year)}
+	for _, table := range tables {
+		if len(table) < 2 {
+			continue
+		}
+
+		switch {
+		case isRendezvousTable(table[0]):
+			parsed.rendezvous = append(parsed.rendezvous, parseRendezvous(table, year)...)
+		case isEvaTable(table[0]):
+			parsed.evas = append(parsed.evas, parseEvas(table, year)...)
+		}
+	}
+	return parsed
+}
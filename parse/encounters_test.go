@@ -0,0 +1,45 @@
+package parse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var encounterTables = [][][]string{
+	{
+		{"Date (UTC)", "Spacecraft", "Event", "Remarks"},
+		{"9 February", "Hope", "Areocentric orbit insertion", "[5]"},
+		{"18 February20:55", "Perseverance", "Landing on Mars", "Landed in Jezero crater"},
+	},
+	{
+		{"Start date/time", "Duration", "End time", "Spacecraft", "Crew", "Remarks"},
+		{"January", "January", "January", "January", "January", "January"},
+		{"27 January16:28", "6 hours56 minutes", "23:24", "ISS Quest", "Michael Hopkins\nVictor Glover", "Prepared the Columbus module"},
+	},
+}
+
+func TestParseYearTables(t *testing.T) {
+	got := parseYearTables(append(encounterTables, statisticsTables...), 2021)
+
+	require.Len(t, got.rendezvous, 2)
+	assert.Equal(t, "Hope", got.rendezvous[0].Spacecraft)
+	assert.Equal(t, "Areocentric orbit insertion", got.rendezvous[0].Event)
+	assert.Empty(t, got.rendezvous[0].Remarks)
+	assert.True(t, got.rendezvous[1].Date.ParsedOk)
+	assert.Equal(t, time.Date(2021, 2, 18, 20, 55, 0, 0, time.UTC), got.rendezvous[1].Date.Timestamp)
+
+	require.Len(t, got.evas, 1)
+	assert.Equal(t, Eva{
+		Start:      parseTimestamp("27 January16:28", 2021),
+		Duration:   "6 hours56 minutes",
+		End:        "23:24",
+		Spacecraft: "ISS Quest",
+		Crew:       []string{"Michael Hopkins", "Victor Glover"},
+		Remarks:    "Prepared the Columbus module",
+	}, got.evas[0])
+
+	assert.Len(t, got.statistics.ByCountry, 2)
+}
@@ -0,0 +1,76 @@
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var slugRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+func slug(input string) string {
+	return strings.Trim(slugRegex.ReplaceAllString(strings.ToLower(input), "-"), "-")
+}
+
+// baseId identifies a launch by its rocket and flight number, falling back to
+// its first payload when there's no flight number. The timestamp is only used
+// as a last resort, since upcoming launches move around between refreshes.
+func (r RocketData) baseId() string {
+	id := slug(r.FlightNumber)
+	if id == "" && len(r.Payload) > 0 {
+		id = slug(r.Payload[0].Payload)
+	}
+	if id == "" {
+		id = slug(r.Timestamp.TimestampClean)
+	}
+	return fmt.Sprintf("%s/%s", slug(r.Rocket), id)
+}
+
+// AssignIds gives every launch without an Id one that is stable across
+// refreshes. Launches that would share an Id are told apart by the order they
+// appear in, e.g. "r-7/sputnik-1" and "r-7/sputnik-1-2".
+func AssignIds(launches []RocketData) {
+	ids := NewIdAssigner()
+	for _, launch := range launches {
+		ids.Take(launch.Id)
+	}
+	for i := range launches {
+		ids.Assign(&launches[i])
+	}
+}
+
+// IdAssigner hands out ids that haven't been taken yet, for when launches
+// are read one at a time
+type IdAssigner struct {
+	seen map[string]bool
+}
+
+func NewIdAssigner() *IdAssigner {
+	return &IdAssigner{seen: map[string]bool{}}
+}
+
+// Take marks id as used, and reports whether it already was
+func (a *IdAssigner) Take(id string) bool {
+	if id == "" {
+		return false
+	}
+	taken := a.seen[id]
+	a.seen[id] = true
+	return taken
+}
+
+// Assign gives launch an id if it doesn't have one
+func (a *IdAssigner) Assign(launch *RocketData) {
+	if launch.Id != "" {
+		a.Take(launch.Id)
+		return
+	}
+
+	base := launch.baseId()
+	id := base
+	for n := 2; a.seen[id]; n++ {
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+	a.seen[id] = true
+	launch.Id = id
+}
@@ -0,0 +1,42 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssignIds(t *testing.T) {
+	launches := []RocketData{
+		{Rocket: " Atlas V 541", FlightNumber: "AV-095"},
+		{Rocket: " Falcon 9 Block 5", Payload: []PayloadData{{Payload: " Starlink × 49"}}},
+		{Rocket: " Falcon 9 Block 5", Payload: []PayloadData{{Payload: " Starlink × 49"}}},
+		{Rocket: "Long March 2D", Timestamp: TimeData{TimestampClean: "Late December"}},
+		{Id: "kept/as-is", Rocket: "Electron"},
+	}
+
+	AssignIds(launches)
+
+	var got []string
+	for _, launch := range launches {
+		got = append(got, launch.Id)
+	}
+	want := []string{
+		"atlas-v-541/av-095",
+		"falcon-9-block-5/starlink-49",
+		"falcon-9-block-5/starlink-49-2",
+		"long-march-2d/late-december",
+		"kept/as-is",
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestIdsIgnoreTimestamp(t *testing.T) {
+	before := []RocketData{{Rocket: "Vulcan Centaur", FlightNumber: "Cert-1", Timestamp: TimeData{TimestampClean: "Early 2022"}}}
+	after := []RocketData{{Rocket: "Vulcan Centaur", FlightNumber: "Cert-1", Timestamp: TimeData{TimestampClean: "4 May"}}}
+
+	AssignIds(before)
+	AssignIds(after)
+
+	assert.Equal(t, before[0].Id, after[0].Id)
+}
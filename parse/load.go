@@ -0,0 +1,108 @@
+package parse
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// The kinds of LoadError, for use with errors.Is
+var (
+	ErrMissingFile    = errors.New("data file not found")
+	ErrUnreadableFile = errors.New("data file couldn't be read")
+	ErrMalformedJson  = errors.New("data file isn't valid JSON")
+	ErrSchemaMismatch = errors.New("data file doesn't match the schema")
+)
+
+// LoadError explains why a data file couldn't be loaded
+type LoadError struct {
+	Filename string
+	Kind     error
+	Err      error
+}
+
+func (e *LoadError) Error() string {
+	return fmt.Sprintf("%s: %v: %v", e.Filename, e.Kind, e.Err)
+}
+
+func (e *LoadError) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+type LoadOptions struct {
+	// Strict rejects files with fields we don't know about, instead of
+	// ignoring them
+	Strict bool
+}
+
+func LoadLaunchDataFromFile(filename string) (AllLaunchData, error) {
+	return LoadLaunchData(filename, LoadOptions{})
+}
+
+// LoadLaunchData reads a data file written by any version of this program,
+// migrating it to the current schema. Errors are always a *LoadError.
+func LoadLaunchData(filename string, options LoadOptions) (AllLaunchData, error) {
+	var response AllLaunchData
+
+	contents, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return response, &LoadError{filename, ErrMissingFile, err}
+	} else if err != nil {
+		return response, &LoadError{filename, ErrUnreadableFile, err}
+	}
+
+	if len(bytes.TrimSpace(contents)) == 0 {
+		return response, &LoadError{filename, ErrMalformedJson, errors.New("file is empty")}
+	}
+
+	// Check the syntax up front, so truncated files are reported as such
+	// rather than as a failed migration
+	if !json.Valid(contents) {
+		var v interface{}
+		err := json.Unmarshal(contents, &v)
+		return response, &LoadError{filename, ErrMalformedJson, err}
+	}
+
+	contents, _, err = Migrate(contents)
+	if err != nil {
+		return response, &LoadError{filename, ErrSchemaMismatch, err}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	if options.Strict {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(&response); err != nil {
+		return response, &LoadError{filename, classifyDecodeError(err), err}
+	}
+
+	// Files written before launches had ids
+	AssignIds(response.OrbitalFlights)
+	AssignIds(response.SuborbitalFlights)
+
+	return response, nil
+}
+
+func classifyDecodeError(err error) error {
+	var syntaxError *json.SyntaxError
+	if errors.As(err, &syntaxError) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrMalformedJson
+	}
+
+	// Type mismatches and, in strict mode, unknown fields. encoding/json
+	// doesn't have a type for the latter.
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) || strings.HasPrefix(err.Error(), "json: unknown field") {
+		return ErrSchemaMismatch
+	}
+
+	return ErrMalformedJson
+}
@@ -0,0 +1,58 @@
+package parse
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, contents string) string {
+		filename := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(filename, []byte(contents), 0o644))
+		return filename
+	}
+
+	tests := []struct {
+		name     string
+		filename string
+		options  LoadOptions
+		want     error
+	}{
+		{"missing", filepath.Join(dir, "launchdata-1960.json"), LoadOptions{}, ErrMissingFile},
+		{"empty", write("empty.json", ""), LoadOptions{}, ErrMalformedJson},
+		{"truncated", write("truncated.json", `{"SchemaVersion": 1, "OrbitalFlights": [{"Rocket": "Sput`), LoadOptions{}, ErrMalformedJson},
+		{"wrong type", write("type.json", `{"SchemaVersion": 1, "OrbitalFlights": [{"Rocket": 7}]}`), LoadOptions{}, ErrSchemaMismatch},
+		{"too new", write("new.json", `{"SchemaVersion": 1000}`), LoadOptions{}, ErrSchemaMismatch},
+		{"unknown field", write("unknown.json", `{"SchemaVersion": 1, "Extra": true}`), LoadOptions{Strict: true}, ErrSchemaMismatch},
+	}
+
+	for _, test := range tests {
+		_, err := LoadLaunchData(test.filename, test.options)
+		assert.ErrorIs(t, err, test.want, test.name)
+
+		var loadError *LoadError
+		require.True(t, errors.As(err, &loadError), test.name)
+		assert.Equal(t, test.filename, loadError.Filename, test.name)
+	}
+
+	_, err := LoadLaunchData(filepath.Join(dir, "launchdata-1960.json"), LoadOptions{})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestLoadIgnoresUnknownFieldsUnlessStrict(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "launchdata-2022.json")
+	require.NoError(t, os.WriteFile(filename, []byte(`{"SchemaVersion": 1, "OrbitalFlights": [{"Rocket": "Electron", "Extra": 1}]}`), 0o644))
+
+	got, err := LoadLaunchData(filename, LoadOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "Electron", got.OrbitalFlights[0].Rocket)
+
+	_, err = LoadLaunchData(filename, LoadOptions{Strict: true})
+	assert.ErrorIs(t, err, ErrSchemaMismatch)
+}
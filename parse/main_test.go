@@ -1,10 +1,17 @@
 package parse
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"launchdata/archive"
+	"launchdata/config"
 	"launchdata/jsonio"
 )
 
@@ -52,3 +59,41 @@ func TestGeneratingUrlsForYearRange(t *testing.T) {
 		t.Errorf("diff (+want,-got:\n%s", diff)
 	}
 }
+
+func TestReparseFromArchive(t *testing.T) {
+	a := archive.Open(t.TempDir())
+	urls := generateUrlsForYearRange(2022, 2022)
+	fetchedAt := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	for i, filename := range []string{"testdata/launches-2022-jan-6-17.json", "testdata/launches-2022-jan-6.json"} {
+		body, err := os.ReadFile(filename)
+		require.NoError(t, err)
+		_, err = a.Put(archive.Entry{Url: urls[i].Url, RevisionId: int64(i + 1), FetchedAt: fetchedAt}, body)
+		require.NoError(t, err)
+	}
+
+	filename := filepath.Join(t.TempDir(), "launchdata-2022.json")
+	result, err := ReparseAndWrite(config.Config{}, a, time.Time{}, 2022, 2022, filename)
+	require.NoError(t, err)
+
+	want, _, err := parseMultipleDates(mustLoad(t, "testdata/launches-2022-jan-6-17.json")[0], 2022)
+	require.NoError(t, err)
+	assert.Len(t, result.Data.OrbitalFlights, len(want))
+	assert.Equal(t, int64(1), result.Sources[0].RevisionId)
+	assert.Equal(t, fetchedAt, result.Sources[1].FetchedAt)
+
+	written, err := LoadLaunchDataFromFile(filename)
+	require.NoError(t, err)
+	assert.Len(t, written.OrbitalFlights, len(result.Data.OrbitalFlights))
+
+	// Nothing had been fetched yet, so there's nothing to reparse
+	_, err = ReparseAndWrite(config.Config{}, a, fetchedAt.Add(-time.Hour), 2022, 2022, filename)
+	assert.Error(t, err)
+}
+
+func mustLoad(t *testing.T, filename string) jsonio.RawResponse {
+	t.Helper()
+	response, err := jsonio.LoadFromFile(filename)
+	require.NoError(t, err)
+	return response
+}
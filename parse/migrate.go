@@ -0,0 +1,107 @@
+package parse
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SchemaVersion is the layout of AllLaunchData written by this version of
+// the code. Bump it, and add a migration, whenever a change to the types in
+// this package would stop older files from loading correctly.
+const SchemaVersion = 1
+
+// A migration upgrades a decoded file from one schema version to the next
+type migration func(doc map[string]interface{}) error
+
+// migrations[i] upgrades a file from version i to version i+1
+var migrations = []migration{
+	migrateV0ToV1,
+}
+
+// documentVersion reads the schema version of a decoded file. Files written
+// before versioning was introduced don't have one, and are version 0.
+func documentVersion(doc map[string]interface{}) (int, error) {
+	raw, ok := doc["SchemaVersion"]
+	if !ok {
+		return 0, nil
+	}
+	version, ok := raw.(float64)
+	if !ok || version != float64(int(version)) {
+		return 0, fmt.Errorf("SchemaVersion %v is not a whole number", raw)
+	}
+	return int(version), nil
+}
+
+// Migrate upgrades a file written with any earlier schema to the current one,
+// returning the version it started at
+func Migrate(contents []byte) ([]byte, int, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(contents, &doc); err != nil {
+		return nil, 0, err
+	}
+
+	from, err := documentVersion(doc)
+	if err != nil {
+		return nil, 0, err
+	}
+	if from > SchemaVersion {
+		return nil, from, fmt.Errorf("schema version %d is newer than the latest version this program understands (%d)", from, SchemaVersion)
+	}
+	if from == SchemaVersion {
+		return contents, from, nil
+	}
+
+	for version := from; version < SchemaVersion; version++ {
+		if err := migrations[version](doc); err != nil {
+			return nil, from, fmt.Errorf("migrating from schema version %d: %w", version, err)
+		}
+		doc["SchemaVersion"] = version + 1
+	}
+
+	migrated, err := json.Marshal(doc)
+	return migrated, from, err
+}
+
+func launchLists(doc map[string]interface{}) [][]interface{} {
+	var lists [][]interface{}
+	for _, key := range []string{"OrbitalFlights", "SuborbitalFlights"} {
+		if launches, ok := doc[key].([]interface{}); ok {
+			lists = append(lists, launches)
+		}
+	}
+	return lists
+}
+
+// Version 0 wrote TimeData.ParseErr as an error interface, which came out as
+// an empty object that can't be decoded again. Version 1 writes the message.
+// Ids were also introduced around then, but they're filled in on load.
+func migrateV0ToV1(doc map[string]interface{}) error {
+	for _, launches := range launchLists(doc) {
+		for _, launch := range launches {
+			launch, ok := launch.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("expected a launch object, got %T", launch)
+			}
+			timestamp, ok := launch["Timestamp"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			switch parseErr := timestamp["ParseErr"].(type) {
+			case nil, string:
+				// already decodable
+			case map[string]interface{}:
+				// The message was lost when it was written
+				if tbd, _ := timestamp["Tbd"].(bool); tbd {
+					timestamp["ParseErr"] = "TBD"
+				} else {
+					timestamp["ParseErr"] = "failed to parse"
+				}
+			default:
+				return fmt.Errorf("unexpected ParseErr %v", parseErr)
+			}
+		}
+	}
+
+	return nil
+}
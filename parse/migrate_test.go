@@ -0,0 +1,49 @@
+package parse
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateFromUnversioned(t *testing.T) {
+	v0 := []byte(`{
+		"OrbitalFlights": [
+			{"Rocket": "Atlas V", "Timestamp": {"TimestampClean": "Mid 2022", "ParsedOk": false, "Tbd": false, "ParseErr": {}}, "Payload": null},
+			{"Rocket": "Soyuz", "Timestamp": {"TimestampClean": "TBD", "ParsedOk": false, "Tbd": true, "ParseErr": {}}, "Payload": null},
+			{"Rocket": "Electron", "Timestamp": {"TimestampClean": "2 May", "ParsedOk": true, "ParseErr": null}, "Payload": null}
+		],
+		"SuborbitalFlights": null
+	}`)
+
+	migrated, from, err := Migrate(v0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, from)
+
+	var got AllLaunchData
+	decoder := json.NewDecoder(bytes.NewReader(migrated))
+	decoder.DisallowUnknownFields()
+	require.NoError(t, decoder.Decode(&got))
+
+	assert.Equal(t, SchemaVersion, got.SchemaVersion)
+	assert.Equal(t, "failed to parse", got.OrbitalFlights[0].Timestamp.ParseErr)
+	assert.Equal(t, "TBD", got.OrbitalFlights[1].Timestamp.ParseErr)
+	assert.Equal(t, "", got.OrbitalFlights[2].Timestamp.ParseErr)
+}
+
+func TestMigrateCurrentVersionIsUnchanged(t *testing.T) {
+	current := []byte(`{"SchemaVersion": 1, "OrbitalFlights": null, "SuborbitalFlights": null}`)
+
+	migrated, from, err := Migrate(current)
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion, from)
+	assert.Equal(t, current, migrated)
+}
+
+func TestMigrateRejectsNewerVersions(t *testing.T) {
+	_, _, err := Migrate([]byte(`{"SchemaVersion": 99}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,30 @@
+package parse
+
+import (
+	"regexp"
+	"strings"
+)
+
+var slashRegex = regexp.MustCompile(`\s*/\s*`)
+
+// NormaliseName tidies the spacing of a vehicle, site or provider name, which
+// wikipedia's tables don't write consistently, e.g. "Proton-M / Briz-M" and
+// "Proton-M/Briz-M" are both "Proton-M/Briz-M"
+func NormaliseName(name string) string {
+	return slashRegex.ReplaceAllString(strings.Join(strings.Fields(name), " "), "/")
+}
+
+// configurationRegex matches the configuration number some vehicles are
+// written with, like Atlas V's fairing, booster and upper stage engine count
+// in "Atlas V 511", or H-IIA's in "H-IIA 202"
+var configurationRegex = regexp.MustCompile(`\s+(N\d{2}|\d{3,4})$`)
+
+// BaseVehicle is the rocket a vehicle name is a configuration of, without
+// the upper stage after a "/", a configuration number or the "?" of an
+// uncertain entry, e.g. "Proton-M/Briz-M", "Atlas V 511" and "Long March 2C?"
+// are "Proton-M", "Atlas V" and "Long March 2C"
+func BaseVehicle(rocket string) string {
+	base, _, _ := strings.Cut(NormaliseName(rocket), "/")
+	base = strings.TrimSpace(strings.TrimRight(base, "?"))
+	return configurationRegex.ReplaceAllString(base, "")
+}
@@ -0,0 +1,40 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormaliseName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"Falcon 9 Block 5":    "Falcon 9 Block 5",
+		" Long March  2C\n":   "Long March 2C",
+		"Proton-M / Briz-M":   "Proton-M/Briz-M",
+		"Long March 2C /SMA":  "Long March 2C/SMA",
+		"Baikonur Site 81/24": "Baikonur Site 81/24",
+		"":                    "",
+	} {
+		assert.Equal(t, expected, NormaliseName(name), name)
+	}
+}
+
+func TestBaseVehicle(t *testing.T) {
+	for rocket, expected := range map[string]string{
+		"Proton-M":                     "Proton-M",
+		"Proton-M/Briz-M":              "Proton-M",
+		"Proton-M / Briz-M":            "Proton-M",
+		"Long March 2C?":               "Long March 2C",
+		"Long March 3C/E/YZ-1":         "Long March 3C",
+		"Falcon 9 Block 5 / SHERPA-FX": "Falcon 9 Block 5",
+		"Atlas V 511":                  "Atlas V",
+		"Atlas V N22":                  "Atlas V",
+		"H-IIA 202":                    "H-IIA",
+		"Delta II 7925":                "Delta II",
+		"Falcon 9":                     "Falcon 9",
+		"Long March 11":                "Long March 11",
+		"":                             "",
+	} {
+		assert.Equal(t, expected, BaseVehicle(rocket), rocket)
+	}
+}
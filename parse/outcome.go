@@ -0,0 +1,79 @@
+package parse
+
+import "strings"
+
+// Outcome is a normalised version of the free text outcomes in the wiki tables
+type Outcome string
+
+const (
+	OutcomeSuccess              Outcome = "success"
+	OutcomeLaunchFailure        Outcome = "launch failure"
+	OutcomePartialLaunchFailure Outcome = "partial launch failure"
+	OutcomeSpacecraftFailure    Outcome = "spacecraft failure"
+	OutcomePlanned              Outcome = "planned"
+	OutcomeUnknown              Outcome = "unknown"
+)
+
+// Checked in order, so the more specific phrases have to come first
+var outcomePhrases = []struct {
+	phrase  string
+	outcome Outcome
+}{
+	{"partial launch failure", OutcomePartialLaunchFailure},
+	{"launch failure", OutcomeLaunchFailure},
+	{"failed to reach orbit", OutcomeLaunchFailure},
+	{"failed to orbit", OutcomeLaunchFailure},
+	{"destroyed", OutcomeLaunchFailure},
+	{"precluded", OutcomeLaunchFailure},
+	{"failure", OutcomeSpacecraftFailure},
+	{"failed", OutcomeSpacecraftFailure},
+	{"malfunction", OutcomeSpacecraftFailure},
+	{"never deployed", OutcomeSpacecraftFailure},
+	{"damaged", OutcomeSpacecraftFailure},
+	{"anomaly", OutcomeSpacecraftFailure},
+	{"success", OutcomeSuccess},
+	{"succsessful", OutcomeSuccess},
+	{"operational", OutcomeSuccess},
+	{"en route", OutcomeSuccess},
+	{"in orbit", OutcomeSuccess},
+	{"landed", OutcomeSuccess},
+	{"deactivated", OutcomeSuccess},
+	{"decommissioned", OutcomeSuccess},
+	{"decayed", OutcomeSuccess},
+	{"planned", OutcomePlanned},
+	{"scheduled", OutcomePlanned},
+}
+
+// ClassifyOutcome normalises a payload's outcome. Anything we don't recognise,
+// including a blank outcome, is OutcomeUnknown.
+func ClassifyOutcome(outcome string) Outcome {
+	outcome = strings.ToLower(outcome)
+	for _, p := range outcomePhrases {
+		if strings.Contains(outcome, p.phrase) {
+			return p.outcome
+		}
+	}
+	return OutcomeUnknown
+}
+
+// Outcome is the outcome of the launch itself, worked out from its payloads.
+// A launch that delivered a payload which then failed was still a successful
+// launch.
+func (r RocketData) Outcome() Outcome {
+	seen := map[Outcome]bool{}
+	for _, payload := range r.Payload {
+		seen[ClassifyOutcome(payload.Outcome)] = true
+	}
+
+	switch {
+	case seen[OutcomeLaunchFailure]:
+		return OutcomeLaunchFailure
+	case seen[OutcomePartialLaunchFailure]:
+		return OutcomePartialLaunchFailure
+	case seen[OutcomeSuccess] || seen[OutcomeSpacecraftFailure]:
+		return OutcomeSuccess
+	case seen[OutcomePlanned]:
+		return OutcomePlanned
+	}
+	return OutcomeUnknown
+}
@@ -0,0 +1,45 @@
+package parse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyOutcome(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Outcome
+	}{
+		{"Successful", OutcomeSuccess},
+		{"Operational", OutcomeSuccess},
+		{"Launch Failure", OutcomeLaunchFailure},
+		{"Partial launch failureOperational", OutcomePartialLaunchFailure},
+		{"Partial spacecraft failure", OutcomeSpacecraftFailure},
+		{"Spacecraft failure; Partial success", OutcomeSpacecraftFailure},
+		{"Failed to reach orbit", OutcomeLaunchFailure},
+		{"Planned", OutcomePlanned},
+		{"", OutcomeUnknown},
+		{"Unknown", OutcomeUnknown},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, ClassifyOutcome(test.input), test.input)
+	}
+}
+
+func TestLaunchOutcome(t *testing.T) {
+	launch := func(outcomes ...string) RocketData {
+		var r RocketData
+		for _, outcome := range outcomes {
+			r.Payload = append(r.Payload, PayloadData{Outcome: outcome})
+		}
+		return r
+	}
+
+	assert.Equal(t, OutcomeSuccess, launch("Operational", "Spacecraft failure").Outcome())
+	assert.Equal(t, OutcomeLaunchFailure, launch("Launch failure", "Launch failure").Outcome())
+	assert.Equal(t, OutcomePartialLaunchFailure, launch("Partial launch failure").Outcome())
+	assert.Equal(t, OutcomePlanned, launch("Planned").Outcome())
+	assert.Equal(t, OutcomeUnknown, launch().Outcome())
+}
@@ -1,14 +1,13 @@
 package parse
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 	"regexp"
 	"strings"
 	"time"
 	"unicode/utf8"
 
+	"launchdata/archive"
 	"launchdata/config"
 	"launchdata/jsonio"
 
@@ -24,9 +23,19 @@ type PayloadData struct {
 	Decay    string
 	Outcome  string
 	Cubesat  bool
+
+	// Set by the satcat command, from a satellite catalog export
+	NoradId        int    `json:",omitempty"`
+	IntlDesignator string `json:",omitempty"`
+	ObjectType     string `json:",omitempty"`
+	Status         string `json:",omitempty"`
+	DecayDate      string `json:",omitempty"`
 }
 
 type RocketData struct {
+	// Id identifies the launch across refreshes of its file, see AssignIds.
+	// It's only unique within the file, see dataset.LaunchId.
+	Id                    string `json:",omitempty"`
 	Timestamp             TimeData
 	Rocket                string
 	FlightNumber          string
@@ -34,6 +43,18 @@ type RocketData struct {
 	LaunchServiceProvider string
 	Notes                 string
 	Payload               []PayloadData
+
+	// These are only known for rockets with a vehicle list, see VehicleLists
+	Boosters    []BoosterData `json:",omitempty"`
+	PayloadMass string        `json:",omitempty"`
+	Customer    string        `json:",omitempty"`
+
+	// Set by reconcile --enrich, from GCAT's launch log
+	GcatLaunchTag string `json:",omitempty"`
+	CosparId      string `json:",omitempty"`
+
+	// Corrections lists the manual corrections applied, see Patch
+	Corrections []Correction `json:",omitempty"`
 }
 
 func (r *RocketData) Render() string {
@@ -44,25 +65,34 @@ func (r *RocketData) Render() string {
 	return formatted.String()
 }
 
+// ParserVersion should be bumped whenever a change to the parser changes what
+// it writes, so cached files can be traced back to the code that made them
+const ParserVersion = 5
+
 type AllLaunchData struct {
+	SchemaVersion     int
 	OrbitalFlights    []RocketData
 	SuborbitalFlights []RocketData
+	// Statistics has wikipedia's summary tables for each year, see Statistics
+	Statistics []Statistics `json:",omitempty"`
+	Rendezvous []Rendezvous `json:",omitempty"`
+	Evas       []Eva        `json:",omitempty"`
 }
 
-func LoadLaunchDataFromFile(filename string) (AllLaunchData, error) {
-	jsonfile, err := os.Open(filename)
-	if err != nil {
-		fmt.Println(err)
-	}
-	defer jsonfile.Close()
+var months mapset.Set[string] = mapset.NewSet("January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December")
 
-	var response AllLaunchData
-	json.NewDecoder(jsonfile).Decode(&response)
-	return response, err
+// IsSpurious spots rows of wikipedia's tables that aren't launches at all,
+// but were kept by older versions of the parser: month headings, which have
+// the month where the rocket should be, and empty rows
+func (r RocketData) IsSpurious() bool {
+	rocket := strings.TrimSpace(r.Rocket)
+	if rocket == "" {
+		return true
+	}
+	fields := strings.Fields(rocket)
+	return months.Contains(fields[len(fields)-1]) && rocket == strings.TrimSpace(r.Timestamp.TimestampRaw)
 }
 
-var months mapset.Set[string] = mapset.NewSet("January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December")
-
 func shouldSkipEntry(entry []string) bool {
 	if len(entry) == 0 {
 		return true
@@ -175,8 +205,9 @@ func parseSingleDate(index *int, data [][]string, year int) (RocketData, error)
 	return rocketData, nil
 }
 
-func parseMultipleDates(data [][]string, year int) ([]RocketData, error) {
+func parseMultipleDates(data [][]string, year int) ([]RocketData, []Diagnostic, error) {
 	var allRocketData []RocketData
+	var diagnostics []Diagnostic
 	now := time.Now()
 
 	// The first 4 entries contain the month and some other header rows
@@ -188,6 +219,7 @@ func parseMultipleDates(data [][]string, year int) ([]RocketData, error) {
 		rocketData, err := parseSingleDate(&index, data, year)
 		if err != nil {
 			fmt.Println(err)
+			diagnostics = append(diagnostics, Diagnostic{Year: year, Message: err.Error()})
 		}
 
 		// Did it launch empty?
@@ -196,52 +228,335 @@ func parseMultipleDates(data [][]string, year int) ([]RocketData, error) {
 			fmt.Println(
 				fmt.Errorf("parsed a rocketData with no payload, probably something has gone wrong:\n %s",
 					litter.Sdump(rocketData)))
+			diagnostics = append(diagnostics, Diagnostic{
+				Year:    year,
+				Message: fmt.Sprintf("%s launch of %q has no payload", rocketData.Timestamp.DateString(), rocketData.Rocket),
+			})
 		}
 
 		allRocketData = append(allRocketData, rocketData)
 	}
 
-	return allRocketData, nil
+	return allRocketData, diagnostics, nil
+}
+
+// Source records where some of the data in a file came from
+type Source struct {
+	Url        string
+	WikiUrl    string
+	RevisionId int64
+	FetchedAt  time.Time
 }
 
-func getAndParse(config config.Config, url UrlInfo) ([]RocketData, error) {
-	response, err := jsonio.Get(config, url.Url)
+// Result is everything produced by parsing a range of years
+type Result struct {
+	Data        AllLaunchData
+	Sources     []Source
+	Diagnostics []Diagnostic
+
+	// launchesPerYear is how many of Data.OrbitalFlights are from each year,
+	// in order
+	launchesPerYear []int
+}
+
+// fetchFunc returns the wikitable2json response for a url, either from the
+// network or from somewhere we've stored it
+type fetchFunc func(url UrlInfo) (jsonio.RawResponse, Source, error)
+
+// networkFetcher requests each url, and keeps a copy of the raw response in
+// the archive if one is configured
+func networkFetcher(config config.Config) fetchFunc {
+	return func(url UrlInfo) (jsonio.RawResponse, Source, error) {
+		source := Source{
+			Url:       url.Url,
+			WikiUrl:   url.WikiUrl,
+			FetchedAt: time.Now().UTC(),
+		}
+
+		if config.DryRun {
+			response, err := jsonio.Get(config, url.Url)
+			return response, source, err
+		}
+
+		revisionId, err := getRevisionId(config, url)
+		if err != nil {
+			fmt.Println(fmt.Errorf("failed to look up the current revision of %s: %w", url.WikiUrl, err))
+		}
+		source.RevisionId = revisionId
+
+		body, err := jsonio.GetRaw(config, url.Url)
+		if err != nil {
+			return nil, source, err
+		}
+
+		if config.ArchiveDir != "" {
+			entry := archive.Entry{
+				Url:        url.Url,
+				RevisionId: source.RevisionId,
+				FetchedAt:  source.FetchedAt,
+			}
+			if _, err := archive.Open(config.ArchiveDir).Put(entry, body); err != nil {
+				fmt.Println(fmt.Errorf("failed to archive response for %s: %w", url.Url, err))
+			}
+		}
+
+		response, err := jsonio.Decode(body)
+		return response, source, err
+	}
+}
+
+// archiveFetcher reads each url from the archive, using the last fetch made
+// at or before asOf
+func archiveFetcher(a *archive.Archive, asOf time.Time) fetchFunc {
+	return func(url UrlInfo) (jsonio.RawResponse, Source, error) {
+		source := Source{
+			Url:     url.Url,
+			WikiUrl: url.WikiUrl,
+		}
+
+		entry, ok, err := a.Latest(url.Url, asOf)
+		if err != nil {
+			return nil, source, err
+		}
+		if !ok {
+			return nil, source, fmt.Errorf("no archived response for %s", url.Url)
+		}
+		source.RevisionId = entry.RevisionId
+		source.FetchedAt = entry.FetchedAt
+
+		body, err := a.Get(entry.Hash)
+		if err != nil {
+			return nil, source, err
+		}
+
+		response, err := jsonio.Decode(body)
+		return response, source, err
+	}
+}
+
+// page is everything parsed from a single url
+type page struct {
+	launches    []RocketData
+	tables      yearTables
+	source      Source
+	diagnostics []Diagnostic
+}
+
+func getAndParse(fetch fetchFunc, url UrlInfo) (page, error) {
+	response, source, err := fetch(url)
+	p := page{launches: []RocketData{}, source: source, tables: yearTables{statistics: Statistics{Year: url.Year}}}
 	if err != nil {
-		return []RocketData{}, err
+		return p, err
+	}
+	if len(response) == 0 {
+		return p, nil
 	}
 
-	launchData, err := parseMultipleDates(response[0], url.Year)
+	p.launches, p.diagnostics, err = parseMultipleDates(response[0], url.Year)
+	if hasYearTables(url) {
+		p.tables = parseYearTables(response[1:], url.Year)
+	}
+	for i := range p.diagnostics {
+		p.diagnostics[i].Url = url.WikiUrl
+	}
 
-	return launchData, err
+	return p, err
 }
 
-func getAndParseMultipleYears(config config.Config, startYear int, endYear int) (AllLaunchData, error) {
-	urls := generateUrlsForYearRange(startYear, endYear)
-	var allLaunchData AllLaunchData
-	for _, url := range urls {
-		launchData, err := getAndParse(config, url)
-		if err != nil {
-			fmt.Printf("Encountered an error: %v\n", err)
+// getAndParseYearPage reads the summary, rendezvous and EVA tables from a year
+// page that doesn't have the launches on it
+func getAndParseYearPage(fetch fetchFunc, url UrlInfo) (yearTables, Source, error) {
+	response, source, err := fetch(url)
+	if err != nil {
+		return yearTables{statistics: Statistics{Year: url.Year}}, source, err
+	}
+	return parseYearTables(response, url.Year), source, nil
+}
+
+// getAndParseMultipleYears parses the launches in the years from startYear to
+// endYear, fetching the vehicle lists that cover them with fetchList
+func getAndParseMultipleYears(fetch fetchFunc, fetchList fetchFunc, startYear int, endYear int) (Result, error) {
+	var result Result
+	for year := startYear; year <= endYear; year++ {
+		var launches []RocketData
+		tables := yearTables{statistics: Statistics{Year: year}}
+
+		for _, url := range generateUrlsForYearRange(year, year) {
+			p, err := getAndParse(fetch, url)
+			if err != nil {
+				fmt.Printf("Encountered an error: %v\n", err)
+				p.diagnostics = append(p.diagnostics, Diagnostic{Year: url.Year, Url: url.WikiUrl, Message: err.Error()})
+			}
+
+			fmt.Printf("Parsed %d orbital launches in %d (%s, %s)\n", len(p.launches), url.Year, url.Url, url.WikiUrl)
+			launches = append(launches, p.launches...)
+			result.Sources = append(result.Sources, p.source)
+			result.Diagnostics = append(result.Diagnostics, p.diagnostics...)
+			if hasYearTables(url) {
+				tables = p.tables
+			}
 		}
 
-		fmt.Printf("Parsed %d orbital launches in %d (%s, %s)\n", len(launchData), url.Year, url.Url, url.WikiUrl)
-		allLaunchData.OrbitalFlights = append(allLaunchData.OrbitalFlights, launchData...)
+		if url, ok := separateYearPageUrl(year); ok {
+			var source Source
+			var err error
+			tables, source, err = getAndParseYearPage(fetch, url)
+			if err != nil {
+				fmt.Printf("Encountered an error: %v\n", err)
+				result.Diagnostics = append(result.Diagnostics, Diagnostic{Year: year, Url: url.WikiUrl, Message: fmt.Sprintf("couldn't read the year page: %v", err)})
+			} else {
+				result.Sources = append(result.Sources, source)
+			}
+		}
+
+		if !tables.statistics.Empty() {
+			diagnostics := crossCheckStatistics(tables.statistics, launches)
+			for i := range diagnostics {
+				diagnostics[i].Url = yearPageUrl(year).WikiUrl
+				fmt.Println(diagnostics[i])
+			}
+			result.Diagnostics = append(result.Diagnostics, diagnostics...)
+			result.Data.Statistics = append(result.Data.Statistics, tables.statistics)
+		}
+		fmt.Printf("Parsed %d deep space rendezvous and %d EVAs in %d\n", len(tables.rendezvous), len(tables.evas), year)
+		result.Data.Rendezvous = append(result.Data.Rendezvous, tables.rendezvous...)
+		result.Data.Evas = append(result.Data.Evas, tables.evas...)
+
+		result.Data.OrbitalFlights = append(result.Data.OrbitalFlights, launches...)
+		result.launchesPerYear = append(result.launchesPerYear, len(launches))
 	}
-	return allLaunchData, nil
+	getAndMergeVehicleLists(fetchList, startYear, endYear, &result)
+	AssignIds(result.Data.OrbitalFlights)
+	return result, nil
 }
 
-func GetAndWrite(config config.Config, startYear int, endYear int, filename string) {
-	if config.DryRun {
+// fetchOnce wraps fetch so each url is only fetched the first time it's
+// asked for. Failures aren't remembered, so they're tried again.
+func fetchOnce(fetch fetchFunc) fetchFunc {
+	type fetched struct {
+		response jsonio.RawResponse
+		source   Source
+	}
+	seen := map[string]fetched{}
+
+	return func(url UrlInfo) (jsonio.RawResponse, Source, error) {
+		if page, ok := seen[url.Url]; ok {
+			return page.response, page.source, nil
+		}
+		response, source, err := fetch(url)
+		if err == nil {
+			seen[url.Url] = fetched{response, source}
+		}
+		return response, source, err
+	}
+}
+
+// Fetcher gets and writes data files from wikipedia. Vehicle list pages cover
+// many years, so a Fetcher only fetches each of them once, however many
+// files it writes.
+type Fetcher struct {
+	config    config.Config
+	fetch     fetchFunc
+	fetchList fetchFunc
+}
+
+func NewFetcher(config config.Config) *Fetcher {
+	fetch := networkFetcher(config)
+	return &Fetcher{config: config, fetch: fetch, fetchList: fetchOnce(fetch)}
+}
+
+func (f *Fetcher) GetAndWrite(startYear int, endYear int, filename string) Result {
+	if f.config.DryRun {
 		fmt.Printf("Dry run: would get and write file %s\n", filename)
+		return Result{}
+	}
+
+	result, _ := getAndParseMultipleYears(f.fetch, f.fetchList, startYear, endYear)
+	applyCorrections(f.config, startYear, endYear, &result)
+
+	write(f.config, result.Data, filename)
+	return result
+}
+
+func GetAndWrite(config config.Config, startYear int, endYear int, filename string) Result {
+	return NewFetcher(config).GetAndWrite(startYear, endYear, filename)
+}
+
+// ReparseAndWrite regenerates a data file from archived responses instead of
+// the network. Responses fetched after asOf are ignored, so passing the time
+// of an old cache run reproduces what it would have written with today's
+// parser.
+func ReparseAndWrite(config config.Config, a *archive.Archive, asOf time.Time, startYear int, endYear int, filename string) (Result, error) {
+	// Don't clobber an existing file with a partial year. Year pages and
+	// vehicle lists only add detail, so an old archive without them is fine.
+	for _, url := range generateUrlsForYearRange(startYear, endYear) {
+		if _, ok, err := a.Latest(url.Url, asOf); err != nil {
+			return Result{}, err
+		} else if !ok {
+			return Result{}, fmt.Errorf("no archived response for %s", url.Url)
+		}
+	}
+
+	if config.DryRun {
+		fmt.Printf("Dry run: would reparse and write file %s\n", filename)
+		return Result{}, nil
+	}
+
+	fetch := archiveFetcher(a, asOf)
+	result, _ := getAndParseMultipleYears(fetch, fetch, startYear, endYear)
+	applyCorrections(config, startYear, endYear, &result)
+
+	write(config, result.Data, filename)
+	return result, nil
+}
+
+// applyCorrections applies the patches in the corrections directory, if
+// there is one, to freshly parsed launches
+func applyCorrections(config config.Config, startYear int, endYear int, result *Result) {
+	if config.CorrectionsDir == "" {
 		return
 	}
 
-	results, _ := getAndParseMultipleYears(config, startYear, endYear)
+	patches, err := LoadCorrections(config.CorrectionsDir)
+	if err != nil {
+		fmt.Println(fmt.Errorf("not applying corrections: %w", err))
+		result.Diagnostics = append(result.Diagnostics, Diagnostic{Year: startYear, Message: fmt.Sprintf("not applying corrections: %v", err)})
+		return
+	}
+
+	// Slicing keeps the launches shared with result, so they're corrected in
+	// place
+	launches := map[int][][]RocketData{}
+	remaining := result.Data.OrbitalFlights
+	for i, n := range result.launchesPerYear {
+		launches[startYear+i] = [][]RocketData{remaining[:n]}
+		remaining = remaining[n:]
+	}
+	if startYear == endYear {
+		launches[startYear] = append(launches[startYear], result.Data.SuborbitalFlights)
+	}
 
+	report := ApplyCorrections(patches, launches)
+	if len(report.Applied) > 0 {
+		fmt.Printf("Applied %d corrections\n", len(report.Applied))
+	}
+	for _, diagnostic := range report.Diagnostics() {
+		fmt.Println(diagnostic)
+		result.Diagnostics = append(result.Diagnostics, diagnostic)
+	}
+}
+
+func write(config config.Config, results AllLaunchData, filename string) {
 	if filename != "" {
 		fmt.Printf("Writing %s\n", filename)
-		if err := jsonio.WriteJsonFile(config, results, filename); err != nil {
+		if err := WriteLaunchDataFile(config, results, filename); err != nil {
 			panic(err)
 		}
 	}
 }
+
+// WriteLaunchDataFile writes data stamped with the current schema version
+func WriteLaunchDataFile(config config.Config, data AllLaunchData, filename string) error {
+	data.SchemaVersion = SchemaVersion
+	return jsonio.WriteJsonFile(config, data, filename)
+}
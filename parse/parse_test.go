@@ -2,6 +2,7 @@ package parse
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 
 	golden "github.com/jimeh/go-golden"
@@ -71,6 +72,16 @@ func TestSkippingIrrelevantEntries(t *testing.T) {
 	}
 }
 
+func TestIsSpurious(t *testing.T) {
+	heading := "\n\n←  Jan\nFeb\nMar\nApr\nMay\nJun\nJul\nAug\nSep\nOct\nNov\nDec →\n\n\n\nFebruary"
+	assert.True(t, RocketData{Rocket: heading, Timestamp: TimeData{TimestampRaw: heading}}.IsSpurious())
+	assert.True(t, RocketData{Rocket: "\n\nMarch", Timestamp: TimeData{TimestampRaw: "\n\nMarch"}}.IsSpurious())
+	assert.True(t, RocketData{Rocket: " "}.IsSpurious())
+	assert.False(t, RocketData{Rocket: "Falcon 9", Timestamp: TimeData{TimestampRaw: "6 January21:49"}}.IsSpurious())
+	// A launch only known to happen in a month
+	assert.False(t, RocketData{Rocket: "Vega-C", Timestamp: TimeData{TimestampRaw: "March"}}.IsSpurious())
+}
+
 func TestCanParseSingleDateWithSinglePayload(t *testing.T) {
 	response, err := jsonio.LoadFromFile("testdata/launches-2022-jan-6.json")
 	require.NoError(t, err)
@@ -105,7 +116,7 @@ func TestCanParseMultipleDates(t *testing.T) {
 	response, err := jsonio.LoadFromFile("testdata/launches-2022-jan-6-17.json")
 	require.NoError(t, err)
 
-	got, err := parseMultipleDates(response[0], 2022)
+	got, _, err := parseMultipleDates(response[0], 2022)
 	require.NoError(t, err)
 
 	verify(t, got)
@@ -115,8 +126,38 @@ func TestCanParseFullWikiPage(t *testing.T) {
 	response, err := jsonio.LoadFromFile("testdata/launches-2022-jan-jun.json")
 	require.NoError(t, err)
 
-	got, err := parseMultipleDates(response[0], 2022)
+	got, _, err := parseMultipleDates(response[0], 2022)
 	require.NoError(t, err)
 
 	verify(t, got)
 }
+
+func TestFetchOnce(t *testing.T) {
+	calls := map[string]int{}
+	failing := true
+	fetch := fetchOnce(func(url UrlInfo) (jsonio.RawResponse, Source, error) {
+		calls[url.Url]++
+		if url.Url == "flaky" && failing {
+			return nil, Source{Url: url.Url}, errors.New("timed out")
+		}
+		return jsonio.RawResponse{{{url.Url}}}, Source{Url: url.Url}, nil
+	})
+
+	for i := 0; i < 3; i++ {
+		response, source, err := fetch(UrlInfo{Url: "list"})
+		require.NoError(t, err)
+		assert.Equal(t, jsonio.RawResponse{{{"list"}}}, response)
+		assert.Equal(t, "list", source.Url)
+	}
+	assert.Equal(t, 1, calls["list"])
+
+	// Failures are tried again
+	_, _, err := fetch(UrlInfo{Url: "flaky"})
+	assert.Error(t, err)
+	failing = false
+	_, _, err = fetch(UrlInfo{Url: "flaky"})
+	assert.NoError(t, err)
+	_, _, err = fetch(UrlInfo{Url: "flaky"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls["flaky"])
+}
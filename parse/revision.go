@@ -0,0 +1,54 @@
+package parse
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+
+	"launchdata/config"
+	"launchdata/jsonio"
+)
+
+const wikiApiUrl = "https://en.wikipedia.org/w/api.php"
+
+type revisionResponse struct {
+	Query struct {
+		Pages []struct {
+			Title     string
+			Missing   bool
+			Revisions []struct {
+				RevId int64
+			}
+		}
+	}
+}
+
+// getRevisionId asks wikipedia for the current revision of the page behind
+// url. wikitable2json doesn't tell us which revision it rendered, so this is
+// the revision that was live at about the time we fetched it.
+func getRevisionId(config config.Config, url UrlInfo) (int64, error) {
+	// WikiUrl is already escaped, so the title can go straight into the query
+	title := path.Base(url.WikiUrl)
+	apiUrl := fmt.Sprintf("%s?action=query&prop=revisions&rvprop=ids&format=json&formatversion=2&titles=%s", wikiApiUrl, title)
+
+	body, err := jsonio.GetRaw(config, apiUrl)
+	if err != nil {
+		return 0, err
+	}
+
+	var response revisionResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return 0, err
+	}
+
+	for _, page := range response.Query.Pages {
+		if page.Missing {
+			return 0, fmt.Errorf("page %q doesn't exist", page.Title)
+		}
+		if len(page.Revisions) > 0 {
+			return page.Revisions[0].RevId, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no revisions returned for %s", title)
+}
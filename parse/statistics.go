@@ -0,0 +1,272 @@
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LaunchStatistic is a row of one of the "Orbital launch statistics" tables
+// on a "YYYY in spaceflight" page
+type LaunchStatistic struct {
+	// Name is the country, rocket family or spaceport the row is about
+	Name            string
+	Country         string `json:",omitempty"`
+	Launches        int
+	Successes       int
+	Failures        int
+	PartialFailures int
+}
+
+type OrbitStatistic struct {
+	Orbit                string
+	Launches             int
+	Achieved             int
+	NotAchieved          int
+	AccidentallyAchieved int
+}
+
+// Statistics are the summary tables for a year, as wikipedia gives them
+type Statistics struct {
+	Year        int
+	ByCountry   []LaunchStatistic `json:",omitempty"`
+	ByRocket    []LaunchStatistic `json:",omitempty"`
+	BySpaceport []LaunchStatistic `json:",omitempty"`
+	ByOrbit     []OrbitStatistic  `json:",omitempty"`
+}
+
+func (s Statistics) Empty() bool {
+	return len(s.ByCountry) == 0 && len(s.ByRocket) == 0 && len(s.BySpaceport) == 0 && len(s.ByOrbit) == 0
+}
+
+type statisticsTable int
+
+const (
+	notStatistics statisticsTable = iota
+	byCountryTable
+	byRocketTable
+	bySpaceportTable
+	byOrbitTable
+)
+
+// The summary tables use lettered notes ("[a]") as well as numbered references
+var footnoteRemovalRegex = regexp.MustCompile(`\[[a-z]\]`)
+
+func cleanCell(cell string) string {
+	return strings.TrimSpace(footnoteRemovalRegex.ReplaceAllString(cleanWikilink(cell), ""))
+}
+
+func normaliseHeader(cell string) string {
+	return strings.ToLower(cleanCell(cell))
+}
+
+// classifyStatisticsTable works out which summary table, if any, a table is
+// from its header row. The tables aren't always in the same order, or even
+// all present, so their position on the page can't be relied on.
+func classifyStatisticsTable(header []string) statisticsTable {
+	if len(header) < 2 {
+		return notStatistics
+	}
+
+	first := normaliseHeader(header[0])
+	hasLaunches := false
+	hasAchieved := false
+	for _, cell := range header {
+		cell = normaliseHeader(cell)
+		hasLaunches = hasLaunches || cell == "launches"
+		hasAchieved = hasAchieved || cell == "achieved"
+	}
+	if !hasLaunches {
+		return notStatistics
+	}
+
+	switch {
+	case strings.HasPrefix(first, "orbital regime") && hasAchieved:
+		return byOrbitTable
+	case first == "country":
+		return byCountryTable
+	case first == "family" || first == "rocket" || first == "rocket family":
+		return byRocketTable
+	case first == "site" || first == "spaceport" || first == "launch site":
+		return bySpaceportTable
+	}
+	return notStatistics
+}
+
+// columnIndex finds the column whose header is one of names
+func columnIndex(header []string, names ...string) int {
+	for i, cell := range header {
+		cell = normaliseHeader(cell)
+		for _, name := range names {
+			if cell == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+var leadingNumberRegex = regexp.MustCompile(`^\d+`)
+
+// cellInt reads the count in a cell, treating anything that isn't a count
+// (e.g. a blank or a dash) as 0
+func cellInt(row []string, index int) int {
+	if index < 0 || index >= len(row) {
+		return 0
+	}
+	n, _ := strconv.Atoi(leadingNumberRegex.FindString(cleanCell(row[index])))
+	return n
+}
+
+func cellString(row []string, index int) string {
+	if index < 0 || index >= len(row) {
+		return ""
+	}
+	return cleanCell(row[index])
+}
+
+// isTotalRow spots the "World" or "Total" rows at the bottom of the tables
+func isTotalRow(name string) bool {
+	name = strings.ToLower(name)
+	return name == "world" || name == "total" || name == "totals"
+}
+
+func parseLaunchStatistics(table [][]string, hasCountry bool) []LaunchStatistic {
+	header := table[0]
+	launches := columnIndex(header, "launches")
+	successes := columnIndex(header, "successes")
+	failures := columnIndex(header, "failures")
+	partialFailures := columnIndex(header, "partial failures")
+	country := -1
+	if hasCountry {
+		country = columnIndex(header, "country")
+	}
+
+	var stats []LaunchStatistic
+	for _, row := range table[1:] {
+		name := cellString(row, 0)
+		if name == "" || isTotalRow(name) {
+			continue
+		}
+		stats = append(stats, LaunchStatistic{
+			Name:            name,
+			Country:         cellString(row, country),
+			Launches:        cellInt(row, launches),
+			Successes:       cellInt(row, successes),
+			Failures:        cellInt(row, failures),
+			PartialFailures: cellInt(row, partialFailures),
+		})
+	}
+	return stats
+}
+
+func parseOrbitStatistics(table [][]string) []OrbitStatistic {
+	header := table[0]
+	launches := columnIndex(header, "launches")
+	achieved := columnIndex(header, "achieved")
+	notAchieved := columnIndex(header, "not achieved")
+	accidentallyAchieved := columnIndex(header, "accidentally achieved")
+
+	var stats []OrbitStatistic
+	for _, row := range table[1:] {
+		name := cellString(row, 0)
+		if name == "" || isTotalRow(name) {
+			continue
+		}
+		stats = append(stats, OrbitStatistic{
+			Orbit:                name,
+			Launches:             cellInt(row, launches),
+			Achieved:             cellInt(row, achieved),
+			NotAchieved:          cellInt(row, notAchieved),
+			AccidentallyAchieved: cellInt(row, accidentallyAchieved),
+		})
+	}
+	return stats
+}
+
+// parseStatistics picks the summary tables out of all the tables on a page
+func parseStatistics(tables [][][]string, year int) Statistics {
+	stats := Statistics{Year: year}
+	for _, table := range tables {
+		if len(table) < 2 {
+			continue
+		}
+
+		switch classifyStatisticsTable(table[0]) {
+		case byCountryTable:
+			stats.ByCountry = parseLaunchStatistics(table, false)
+		case byRocketTable:
+			stats.ByRocket = parseLaunchStatistics(table, true)
+		case bySpaceportTable:
+			stats.BySpaceport = parseLaunchStatistics(table, true)
+		case byOrbitTable:
+			stats.ByOrbit = parseOrbitStatistics(table)
+		}
+	}
+	return stats
+}
+
+// crossCheckStatistics compares wikipedia's summary tables with what we can
+// work out from the launches we parsed, reporting where they disagree. Rocket
+// families and spaceports are matched by prefix, since the launch tables name
+// the specific variant ("Falcon 9 Block 5") or pad ("Kennedy LC-39A").
+func crossCheckStatistics(stats Statistics, launches []RocketData) []Diagnostic {
+	var diagnostics []Diagnostic
+	report := func(format string, args ...interface{}) {
+		diagnostics = append(diagnostics, Diagnostic{Year: stats.Year, Message: fmt.Sprintf(format, args...)})
+	}
+
+	var yearLaunches []RocketData
+	for _, launch := range launches {
+		if launch.Timestamp.ParsedOk && launch.Timestamp.Timestamp.Year() != stats.Year {
+			continue
+		}
+		yearLaunches = append(yearLaunches, launch)
+	}
+
+	if len(stats.ByCountry) > 0 {
+		total, failures := 0, 0
+		for _, country := range stats.ByCountry {
+			total += country.Launches
+			failures += country.Failures
+		}
+		if total != len(yearLaunches) {
+			report("summary tables list %d orbital launches, but %d were parsed", total, len(yearLaunches))
+		}
+
+		parsedFailures := 0
+		for _, launch := range yearLaunches {
+			if launch.Outcome() == OutcomeLaunchFailure {
+				parsedFailures++
+			}
+		}
+		if failures != parsedFailures {
+			report("summary tables list %d launch failures, but %d were parsed", failures, parsedFailures)
+		}
+	}
+
+	countMatching := func(name string, field func(RocketData) string) int {
+		name = strings.ToLower(name)
+		count := 0
+		for _, launch := range yearLaunches {
+			if strings.HasPrefix(strings.ToLower(strings.TrimSpace(field(launch))), name) {
+				count++
+			}
+		}
+		return count
+	}
+
+	for _, rocket := range stats.ByRocket {
+		if parsed := countMatching(rocket.Name, func(r RocketData) string { return r.Rocket }); parsed != rocket.Launches {
+			report("summary tables list %d launches of %s, but %d were parsed", rocket.Launches, rocket.Name, parsed)
+		}
+	}
+	for _, site := range stats.BySpaceport {
+		if parsed := countMatching(site.Name, func(r RocketData) string { return r.LaunchSite }); parsed != site.Launches {
+			report("summary tables list %d launches from %s, but %d were parsed", site.Launches, site.Name, parsed)
+		}
+	}
+
+	return diagnostics
+}
@@ -0,0 +1,103 @@
+package parse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Laid out the way wikitable2json returns the summary tables on a "YYYY in
+// spaceflight" page, trimmed down
+var statisticsTables = [][][]string{
+	{
+		{"Date and time (UTC)", "Rocket", "Flight number", "Launch site", "LSP"},
+	},
+	{
+		{"Country", "Launches", "Successes", "Failures", "Partial failures", "Remarks"},
+		{"China", "2", "1", "1", "0", ""},
+		{"United States[a]", "1", "1", "0", "0", ""},
+		{"World", "3", "2", "1", "0", ""},
+	},
+	{
+		{"Family", "Country", "Launches", "Successes", "Failures", "Partial failures", "Remarks"},
+		{"Long March", "China", "2", "1", "1", "0", ""},
+		{"Falcon", "United States", "1", "1", "0", "0", "Maiden flight"},
+	},
+	{
+		{"Site", "Country", "Launches", "Successes", "Failures", "Partial failures", "Remarks"},
+		{"Jiuquan", "China", "2", "1", "1", "0", ""},
+		{"Cape Canaveral", "United States", "1", "1", "0", "0", ""},
+	},
+	{
+		{"Orbital regime", "Launches", "Achieved", "Not achieved", "Accidentally achieved", "Remarks"},
+		{"Low Earth", "3", "2", "1", "0", ""},
+		{"Geosynchronous / GTO", "0", "0", "0", "—", ""},
+		{"Total", "3", "2", "1", "0", ""},
+	},
+}
+
+func TestParseStatistics(t *testing.T) {
+	got := parseStatistics(statisticsTables, 2015)
+
+	assert.Equal(t, Statistics{
+		Year: 2015,
+		ByCountry: []LaunchStatistic{
+			{Name: "China", Launches: 2, Successes: 1, Failures: 1},
+			{Name: "United States", Launches: 1, Successes: 1},
+		},
+		ByRocket: []LaunchStatistic{
+			{Name: "Long March", Country: "China", Launches: 2, Successes: 1, Failures: 1},
+			{Name: "Falcon", Country: "United States", Launches: 1, Successes: 1},
+		},
+		BySpaceport: []LaunchStatistic{
+			{Name: "Jiuquan", Country: "China", Launches: 2, Successes: 1, Failures: 1},
+			{Name: "Cape Canaveral", Country: "United States", Launches: 1, Successes: 1},
+		},
+		ByOrbit: []OrbitStatistic{
+			{Orbit: "Low Earth", Launches: 3, Achieved: 2, NotAchieved: 1},
+			{Orbit: "Geosynchronous / GTO"},
+		},
+	}, got)
+}
+
+func TestParseStatisticsIgnoresOtherTables(t *testing.T) {
+	got := parseStatistics(statisticsTables[:1], 2015)
+	assert.True(t, got.Empty())
+}
+
+func TestCrossCheckStatistics(t *testing.T) {
+	stats := parseStatistics(statisticsTables, 2015)
+	launch := func(rocket string, site string, outcome string) RocketData {
+		return RocketData{
+			Timestamp:  TimeData{Timestamp: time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC), ParsedOk: true},
+			Rocket:     rocket,
+			LaunchSite: site,
+			Payload:    []PayloadData{{Outcome: outcome}},
+		}
+	}
+
+	launches := []RocketData{
+		launch("Long March 2D", "Jiuquan LA-4", "Operational"),
+		launch("Long March 4B", "Jiuquan LA-4", "Launch failure"),
+		launch("Falcon 9 v1.1", "Cape Canaveral SLC-40", "Operational"),
+	}
+	assert.Empty(t, crossCheckStatistics(stats, launches))
+
+	// Missing a launch, and one from the next year that shouldn't count
+	launches = []RocketData{
+		launches[0],
+		launches[2],
+		launch("Long March 4B", "Jiuquan LA-4", "Launch failure"),
+	}
+	launches[2].Timestamp.Timestamp = time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	diagnostics := crossCheckStatistics(stats, launches)
+	require.Len(t, diagnostics, 4)
+	assert.Equal(t, "summary tables list 3 orbital launches, but 2 were parsed", diagnostics[0].Message)
+	assert.Equal(t, "summary tables list 1 launch failures, but 0 were parsed", diagnostics[1].Message)
+	assert.Equal(t, "summary tables list 2 launches of Long March, but 1 were parsed", diagnostics[2].Message)
+	assert.Equal(t, "summary tables list 2 launches from Jiuquan, but 1 were parsed", diagnostics[3].Message)
+	assert.Equal(t, 2015, diagnostics[0].Year)
+}
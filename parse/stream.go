@@ -0,0 +1,238 @@
+package parse
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IsNdjson is whether filename holds newline-delimited JSON, one launch per
+// line, rather than an AllLaunchData document
+func IsNdjson(filename string) bool {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".ndjson", ".jsonl":
+		return true
+	}
+	return false
+}
+
+// LaunchIterator decodes launches one at a time, so a file doesn't have to
+// fit in memory. Use it like bufio.Scanner:
+//
+//	launches, err := OpenLaunches(filename, options)
+//	...
+//	defer launches.Close()
+//	for launches.Next() {
+//		launch := launches.Launch()
+//	}
+//	err = launches.Err()
+type LaunchIterator struct {
+	filename string
+	file     *os.File
+	decoder  *json.Decoder
+	strict   bool
+	ndjson   bool
+	ids      *IdAssigner
+
+	// versioned is whether SchemaVersion has been read, list is the
+	// AllLaunchData field being read and inList whether we're inside its array
+	versioned bool
+	list      string
+	inList    bool
+
+	// loaded holds the launches of files that had to be loaded whole
+	loaded []RocketData
+	// suborbital is how many of the loaded launches are suborbital, at the end
+	suborbital int
+
+	launch       RocketData
+	isSuborbital bool
+	err          error
+}
+
+// OpenLaunches opens a data file, or an NDJSON file as IsNdjson decides, to
+// be read a launch at a time. Data files written with an older schema are
+// loaded whole and migrated instead, since they can't be streamed.
+func OpenLaunches(filename string, options LoadOptions) (*LaunchIterator, error) {
+	f, err := os.Open(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, &LoadError{filename, ErrMissingFile, err}
+	} else if err != nil {
+		return nil, &LoadError{filename, ErrUnreadableFile, err}
+	}
+
+	it := &LaunchIterator{
+		filename: filename,
+		file:     f,
+		decoder:  json.NewDecoder(bufio.NewReader(f)),
+		strict:   options.Strict,
+		ndjson:   IsNdjson(filename),
+		ids:      NewIdAssigner(),
+	}
+	if options.Strict {
+		it.decoder.DisallowUnknownFields()
+	}
+	if it.ndjson {
+		return it, nil
+	}
+
+	if err := it.expectDelim('{'); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return it, nil
+}
+
+func (it *LaunchIterator) Close() error {
+	return it.file.Close()
+}
+
+// Launch is the launch Next just read
+func (it *LaunchIterator) Launch() RocketData {
+	return it.launch
+}
+
+// Suborbital is whether the launch Next just read is from SuborbitalFlights
+func (it *LaunchIterator) Suborbital() bool {
+	return it.isSuborbital
+}
+
+// Err is the error that stopped Next, if any. Errors are always a *LoadError.
+func (it *LaunchIterator) Err() error {
+	return it.err
+}
+
+func (it *LaunchIterator) fail(kind error, err error) bool {
+	it.err = &LoadError{it.filename, kind, err}
+	return false
+}
+
+func (it *LaunchIterator) expectDelim(delim json.Delim) error {
+	token, err := it.decoder.Token()
+	if err != nil {
+		return &LoadError{it.filename, ErrMalformedJson, err}
+	}
+	if token != delim {
+		return &LoadError{it.filename, ErrSchemaMismatch, fmt.Errorf("expected %v, found %v", delim, token)}
+	}
+	return nil
+}
+
+func (it *LaunchIterator) decodeLaunch() bool {
+	var launch RocketData
+	if err := it.decoder.Decode(&launch); err != nil {
+		return it.fail(classifyDecodeError(err), err)
+	}
+	it.ids.Assign(&launch)
+	it.launch = launch
+	it.isSuborbital = it.list == "SuborbitalFlights"
+	return true
+}
+
+// Next reads the next launch, returning false at the end of the file or on
+// an error
+func (it *LaunchIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	if it.loaded != nil {
+		if len(it.loaded) == 0 {
+			return false
+		}
+		it.launch = it.loaded[0]
+		it.isSuborbital = len(it.loaded) <= it.suborbital
+		it.loaded = it.loaded[1:]
+		return true
+	}
+
+	if it.ndjson {
+		if !it.decoder.More() {
+			return false
+		}
+		return it.decodeLaunch()
+	}
+
+	for {
+		if it.inList {
+			if it.decoder.More() {
+				return it.decodeLaunch()
+			}
+			if err := it.expectDelim(']'); err != nil {
+				it.err = err
+				return false
+			}
+			it.inList = false
+		}
+
+		if !it.decoder.More() {
+			if err := it.expectDelim('}'); err != nil {
+				it.err = err
+			}
+			return false
+		}
+
+		token, err := it.decoder.Token()
+		if err != nil {
+			return it.fail(ErrMalformedJson, err)
+		}
+		key, _ := token.(string)
+		switch key {
+		case "SchemaVersion":
+			var version int
+			if err := it.decoder.Decode(&version); err != nil {
+				return it.fail(ErrSchemaMismatch, err)
+			}
+			if version != SchemaVersion {
+				return it.loadWhole()
+			}
+			it.versioned = true
+		case "OrbitalFlights", "SuborbitalFlights":
+			if !it.versioned {
+				// SchemaVersion is written first, so this is an older file
+				return it.loadWhole()
+			}
+			it.list = key
+			// An empty list is written as null
+			token, err := it.decoder.Token()
+			if err != nil {
+				return it.fail(ErrMalformedJson, err)
+			}
+			if token == json.Delim('[') {
+				it.inList = true
+			} else if token != nil {
+				return it.fail(ErrSchemaMismatch, fmt.Errorf("%s should be a list, found %v", key, token))
+			}
+		case "Statistics", "Rendezvous", "Evas":
+			// These are small enough to skip over whole
+			var skipped json.RawMessage
+			if err := it.decoder.Decode(&skipped); err != nil {
+				return it.fail(ErrMalformedJson, err)
+			}
+		default:
+			if it.strict {
+				return it.fail(ErrSchemaMismatch, fmt.Errorf("json: unknown field %q", key))
+			}
+			var skipped json.RawMessage
+			if err := it.decoder.Decode(&skipped); err != nil {
+				return it.fail(ErrMalformedJson, err)
+			}
+		}
+	}
+}
+
+// loadWhole falls back to loading and migrating the whole file
+func (it *LaunchIterator) loadWhole() bool {
+	data, err := LoadLaunchData(it.filename, LoadOptions{Strict: it.strict})
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.loaded = append(append([]RocketData{}, data.OrbitalFlights...), data.SuborbitalFlights...)
+	it.suborbital = len(data.SuborbitalFlights)
+	return it.Next()
+}
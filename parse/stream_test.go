@@ -0,0 +1,109 @@
+package parse
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type streamed struct {
+	Id         string
+	Rocket     string
+	Suborbital bool
+}
+
+func readAll(t *testing.T, filename string, options LoadOptions) ([]streamed, error) {
+	t.Helper()
+	launches, err := OpenLaunches(filename, options)
+	if err != nil {
+		return nil, err
+	}
+	defer launches.Close()
+
+	var got []streamed
+	for launches.Next() {
+		launch := launches.Launch()
+		got = append(got, streamed{launch.Id, launch.Rocket, launches.Suborbital()})
+	}
+	return got, launches.Err()
+}
+
+func TestOpenLaunchesStreamsCurrentFiles(t *testing.T) {
+	data := AllLaunchData{
+		SchemaVersion: SchemaVersion,
+		OrbitalFlights: []RocketData{
+			{Id: "electron/f1", Rocket: "Electron"},
+			{Rocket: "Falcon 9", FlightNumber: "F9-1"},
+		},
+		SuborbitalFlights: []RocketData{{Id: "new-shepard/ns-1", Rocket: "New Shepard"}},
+		Statistics:        []Statistics{{Year: 2022}},
+	}
+	contents, err := json.MarshalIndent(data, "", "  ")
+	require.NoError(t, err)
+	filename := filepath.Join(t.TempDir(), "launchdata-2022.json")
+	require.NoError(t, os.WriteFile(filename, contents, 0o644))
+
+	got, err := readAll(t, filename, LoadOptions{Strict: true})
+	require.NoError(t, err)
+	assert.Equal(t, []streamed{
+		{"electron/f1", "Electron", false},
+		{"falcon-9/f9-1", "Falcon 9", false},
+		{"new-shepard/ns-1", "New Shepard", true},
+	}, got)
+}
+
+func TestOpenLaunchesMigratesOlderFiles(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "launchdata-1957.json")
+	require.NoError(t, os.WriteFile(filename, []byte(`{"OrbitalFlights": [{"Rocket": "Sputnik", "FlightNumber": "1"}], "SuborbitalFlights": null}`), 0o644))
+
+	got, err := readAll(t, filename, LoadOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []streamed{{"sputnik/1", "Sputnik", false}}, got)
+}
+
+func TestOpenLaunchesReadsNdjson(t *testing.T) {
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	require.NoError(t, encoder.Encode(RocketData{Id: "electron/f1", Rocket: "Electron"}))
+	require.NoError(t, encoder.Encode(RocketData{Id: "electron/f2", Rocket: "Electron"}))
+	filename := filepath.Join(t.TempDir(), "launches.ndjson")
+	require.NoError(t, os.WriteFile(filename, b.Bytes(), 0o644))
+
+	got, err := readAll(t, filename, LoadOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []streamed{{"electron/f1", "Electron", false}, {"electron/f2", "Electron", false}}, got)
+}
+
+func TestOpenLaunchesErrors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, contents string) string {
+		filename := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(filename, []byte(contents), 0o644))
+		return filename
+	}
+
+	tests := []struct {
+		name     string
+		filename string
+		options  LoadOptions
+		want     error
+	}{
+		{"missing", filepath.Join(dir, "launchdata-1960.json"), LoadOptions{}, ErrMissingFile},
+		{"empty", write("empty.json", ""), LoadOptions{}, ErrMalformedJson},
+		{"truncated", write("truncated.json", `{"SchemaVersion": 1, "OrbitalFlights": [{"Rocket": "Sput`), LoadOptions{}, ErrMalformedJson},
+		{"wrong type", write("type.json", `{"SchemaVersion": 1, "OrbitalFlights": [{"Rocket": 7}]}`), LoadOptions{}, ErrSchemaMismatch},
+		{"too new", write("new.json", `{"SchemaVersion": 1000}`), LoadOptions{}, ErrSchemaMismatch},
+		{"unknown field", write("unknown.json", `{"SchemaVersion": 1, "Extra": true}`), LoadOptions{Strict: true}, ErrSchemaMismatch},
+		{"bad line", write("bad.ndjson", "{\"Rocket\": \"Electron\"}\n{\"Rocket\": \n"), LoadOptions{}, ErrMalformedJson},
+	}
+
+	for _, test := range tests {
+		_, err := readAll(t, test.filename, test.options)
+		assert.ErrorIs(t, err, test.want, test.name)
+	}
+}
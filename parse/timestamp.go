@@ -13,7 +13,7 @@ type TimeData struct {
 	Timestamp      time.Time
 	Tbd            bool
 	ParsedOk       bool
-	ParseErr       error
+	ParseErr       string `json:",omitempty"`
 }
 
 func (t TimeData) LaunchedAlready(now time.Time) bool {
@@ -95,7 +95,9 @@ func parseTimestamp(raw string, year int) TimeData {
 		Timestamp:      timestamp,
 		Tbd:            tbd,
 		ParsedOk:       err == nil,
-		ParseErr:       err,
+	}
+	if err != nil {
+		time.ParseErr = err.Error()
 	}
 
 	return time
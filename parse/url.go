@@ -2,6 +2,7 @@ package parse
 
 import (
 	"fmt"
+	"time"
 )
 
 const (
@@ -9,6 +10,15 @@ const (
 	baseWikiUrl = "https://en.wikipedia.org/wiki"
 )
 
+// FirstYear is the earliest year with a launch table on wikipedia
+const FirstYear = 1951
+
+// LatestYear is the last year there's a page for. Pages for the current year
+// list upcoming launches, so they're worth fetching even before they happen.
+func LatestYear() int {
+	return time.Now().Year()
+}
+
 type UrlInfo struct {
 	Year    int
 	Url     string
@@ -42,3 +52,28 @@ func generateUrlsForYearRange(startYear int, endYear int) []UrlInfo {
 
 	return urls
 }
+
+// yearPageUrl is the "YYYY in spaceflight" page for a year, which still has
+// the summary, rendezvous and EVA tables once the launches moved to their own
+// pages
+func yearPageUrl(year int) UrlInfo {
+	return UrlInfo{
+		Year:    year,
+		Url:     fmt.Sprintf("%s/%d_in_spaceflight", baseUrl, year),
+		WikiUrl: fmt.Sprintf("%s/%d_in_spaceflight", baseWikiUrl, year),
+	}
+}
+
+// hasYearTables reports whether the launch page at url is also the year page
+func hasYearTables(url UrlInfo) bool {
+	return url.Year < 2021
+}
+
+// separateYearPageUrl returns the year page, if it isn't one of the launch
+// pages
+func separateYearPageUrl(year int) (UrlInfo, bool) {
+	if year < 2021 {
+		return UrlInfo{}, false
+	}
+	return yearPageUrl(year), true
+}
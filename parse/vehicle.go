@@ -0,0 +1,299 @@
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BoosterData is a first stage or side booster that flew on a launch, as
+// given by a vehicle list page
+type BoosterData struct {
+	Serial string
+	// Flight is how many times this booster has flown, counting this launch,
+	// when the page says
+	Flight int `json:",omitempty"`
+	// LandingType is where the landing was attempted, e.g. "OCISLY" or "LZ-1"
+	LandingType string `json:",omitempty"`
+	// LandingOutcome is "Success", "Failure", "No attempt" and so on
+	LandingOutcome string `json:",omitempty"`
+}
+
+// VehicleListPage is one of the pages a vehicle's launches are split across.
+// LastYear is 0 for the page that has the current launches.
+type VehicleListPage struct {
+	Title     string
+	FirstYear int
+	LastYear  int
+}
+
+func (p VehicleListPage) covers(startYear int, endYear int) bool {
+	return p.FirstYear <= endYear && (p.LastYear == 0 || p.LastYear >= startYear)
+}
+
+func (p VehicleListPage) url() UrlInfo {
+	return UrlInfo{
+		Year:    p.FirstYear,
+		Url:     fmt.Sprintf("%s/%s", baseUrl, p.Title),
+		WikiUrl: fmt.Sprintf("%s/%s", baseWikiUrl, p.Title),
+	}
+}
+
+// VehicleList is a family of rockets with its own list of launches on
+// wikipedia, which has more detail than the yearly tables
+type VehicleList struct {
+	Name string
+	// RocketPrefix picks out the launches in the yearly tables that this list
+	// covers
+	RocketPrefix string
+	Pages        []VehicleListPage
+}
+
+// VehicleLists are the vehicle list pages we read. Wikipedia splits these
+// pages up as they grow, so the titles need updating now and then.
+var VehicleLists = []VehicleList{
+	{
+		Name:         "Falcon 9 and Falcon Heavy",
+		RocketPrefix: "Falcon",
+		Pages: []VehicleListPage{
+			{Title: "List_of_Falcon_9_and_Falcon_Heavy_launches_(2010%E2%80%932019)", FirstYear: 2010, LastYear: 2019},
+			{Title: "List_of_Falcon_9_and_Falcon_Heavy_launches_(2020%E2%80%932022)", FirstYear: 2020, LastYear: 2022},
+			{Title: "List_of_Falcon_9_and_Falcon_Heavy_launches", FirstYear: 2023},
+		},
+	},
+}
+
+// VehicleLaunch is a row of a vehicle list page
+type VehicleLaunch struct {
+	FlightNumber  string
+	Timestamp     TimeData
+	Version       string
+	Boosters      []BoosterData
+	LaunchSite    string
+	Payload       string
+	PayloadMass   string
+	Orbit         string
+	Customer      string
+	LaunchOutcome string
+}
+
+// vehicleColumns maps the columns of a vehicle list table to the fields of
+// VehicleLaunch. Each list lays its tables out a little differently, so every
+// field is found by the names its column goes by.
+type vehicleColumns struct {
+	flightNumber, date, version, launchSite, payload, payloadMass, orbit, customer, outcome, landing int
+}
+
+func columnIndexWithPrefix(header []string, prefixes ...string) int {
+	for i, cell := range header {
+		cell = normaliseHeader(cell)
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(cell, prefix) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func findVehicleColumns(header []string) (vehicleColumns, bool) {
+	columns := vehicleColumns{
+		flightNumber: columnIndexWithPrefix(header, "flight no", "flight number", "no."),
+		date:         columnIndexWithPrefix(header, "date"),
+		version:      columnIndexWithPrefix(header, "version", "vehicle", "booster"),
+		launchSite:   columnIndexWithPrefix(header, "launch site", "site"),
+		payload:      columnIndex(header, "payload", "payloads", "payload(s)"),
+		payloadMass:  columnIndexWithPrefix(header, "payload mass", "mass"),
+		orbit:        columnIndexWithPrefix(header, "orbit"),
+		customer:     columnIndexWithPrefix(header, "customer"),
+		outcome:      columnIndexWithPrefix(header, "launch outcome", "outcome"),
+		landing:      columnIndexWithPrefix(header, "booster landing", "landing"),
+	}
+	// "Version, booster" comes before "Booster landing", so make sure the
+	// version column didn't pick up the landing one
+	if columns.version == columns.landing {
+		columns.version = columnIndexWithPrefix(header, "version", "vehicle")
+	}
+
+	return columns, columns.date >= 0 && columns.version >= 0 && columns.landing >= 0
+}
+
+var (
+	boosterSerialRegex = regexp.MustCompile(`B(\d{4})(?:\.(\d+))?`)
+	landingRegex       = regexp.MustCompile(`(?i)(success|failure|no attempt|precluded|controlled|uncontrolled)\s*(?:\(([^)]*)\))?`)
+	yearTimeRegex      = regexp.MustCompile(`(\d{4}),?\s*(\d{1,2}:\d{2})`)
+)
+
+// parseBoosters reads serials like "B1060.4" out of a version cell, and pairs
+// them up with the landings, which are listed in the same order
+func parseBoosters(version string, landing string) []BoosterData {
+	var boosters []BoosterData
+	for _, match := range boosterSerialRegex.FindAllStringSubmatch(version, -1) {
+		booster := BoosterData{Serial: "B" + match[1]}
+		booster.Flight, _ = strconv.Atoi(match[2])
+		boosters = append(boosters, booster)
+	}
+
+	landings := landingRegex.FindAllStringSubmatch(landing, -1)
+	for i := 0; i < len(boosters) && i < len(landings); i++ {
+		boosters[i].LandingOutcome = strings.TrimSpace(landings[i][1])
+		boosters[i].LandingType = strings.TrimSpace(landings[i][2])
+	}
+	return boosters
+}
+
+// parseVehicleTimestamp reads the dates on vehicle list pages, which include
+// the year, unlike the yearly tables
+func parseVehicleTimestamp(raw string) TimeData {
+	cleaned := cleanCell(raw)
+	normalised := yearTimeRegex.ReplaceAllString(cleaned, "$1 $2")
+
+	data := TimeData{TimestampRaw: raw, TimestampClean: cleaned}
+	formats := []string{
+		"2 January 2006 15:04:05",
+		"2 January 2006 15:04",
+		"2 January 2006",
+		"January 2, 2006 15:04",
+		"January 2, 2006",
+	}
+	for _, format := range formats {
+		if t, err := time.Parse(format, normalised); err == nil {
+			data.Timestamp = t
+			data.ParsedOk = true
+			return data
+		}
+	}
+	data.ParseErr = "failed to parse"
+	return data
+}
+
+func parseVehicleTable(table [][]string) []VehicleLaunch {
+	columns, ok := findVehicleColumns(table[0])
+	if !ok {
+		return nil
+	}
+
+	var launches []VehicleLaunch
+	for _, row := range table[1:] {
+		if isSpanningRow(row) || columns.date >= len(row) {
+			continue
+		}
+		timestamp := parseVehicleTimestamp(row[columns.date])
+		if !timestamp.ParsedOk {
+			continue
+		}
+
+		launches = append(launches, VehicleLaunch{
+			FlightNumber:  cellString(row, columns.flightNumber),
+			Timestamp:     timestamp,
+			Version:       cellString(row, columns.version),
+			Boosters:      parseBoosters(cellString(row, columns.version), cellString(row, columns.landing)),
+			LaunchSite:    cellString(row, columns.launchSite),
+			Payload:       cellString(row, columns.payload),
+			PayloadMass:   cellString(row, columns.payloadMass),
+			Orbit:         cellString(row, columns.orbit),
+			Customer:      cellString(row, columns.customer),
+			LaunchOutcome: cellString(row, columns.outcome),
+		})
+	}
+	return launches
+}
+
+// parseVehicleList reads every launch table on a vehicle list page. Tables
+// that aren't launch tables, like the summaries at the top, are skipped.
+func parseVehicleList(tables [][][]string) []VehicleLaunch {
+	var launches []VehicleLaunch
+	for _, table := range tables {
+		if len(table) < 2 {
+			continue
+		}
+		launches = append(launches, parseVehicleTable(table)...)
+	}
+	return launches
+}
+
+// vehicleMatchWindow is how far apart the times on a vehicle list and in the
+// yearly tables can be for them to be the same launch. They're usually the
+// same, but one page is sometimes updated with the actual time before the
+// other.
+const vehicleMatchWindow = 6 * time.Hour
+
+// mergeVehicleLaunches copies the booster, payload mass and customer from
+// vehicleLaunches onto the matching launches of list's rockets, matching by
+// time. Vehicle launches without a match are returned.
+func mergeVehicleLaunches(list VehicleList, launches []RocketData, vehicleLaunches []VehicleLaunch) []VehicleLaunch {
+	matched := map[int]bool{}
+	var unmatched []VehicleLaunch
+
+	for _, vehicleLaunch := range vehicleLaunches {
+		best := -1
+		var bestGap time.Duration
+		for i, launch := range launches {
+			if matched[i] || !launch.Timestamp.ParsedOk ||
+				!strings.HasPrefix(strings.ToLower(strings.TrimSpace(launch.Rocket)), strings.ToLower(list.RocketPrefix)) {
+				continue
+			}
+
+			gap := launch.Timestamp.Timestamp.Sub(vehicleLaunch.Timestamp.Timestamp)
+			if gap < 0 {
+				gap = -gap
+			}
+			if gap <= vehicleMatchWindow && (best < 0 || gap < bestGap) {
+				best, bestGap = i, gap
+			}
+		}
+
+		if best < 0 {
+			unmatched = append(unmatched, vehicleLaunch)
+			continue
+		}
+
+		matched[best] = true
+		launches[best].Boosters = vehicleLaunch.Boosters
+		launches[best].PayloadMass = vehicleLaunch.PayloadMass
+		launches[best].Customer = vehicleLaunch.Customer
+	}
+
+	return unmatched
+}
+
+// getAndMergeVehicleLists fetches the vehicle list pages that cover the years
+// being parsed and merges them into result. Vehicle lists only add detail, so
+// failing to read one is a diagnostic rather than an error.
+func getAndMergeVehicleLists(fetch fetchFunc, startYear int, endYear int, result *Result) {
+	for _, list := range VehicleLists {
+		for _, listPage := range list.Pages {
+			if !listPage.covers(startYear, endYear) {
+				continue
+			}
+
+			url := listPage.url()
+			response, source, err := fetch(url)
+			if err != nil {
+				fmt.Printf("Encountered an error: %v\n", err)
+				result.Diagnostics = append(result.Diagnostics, Diagnostic{Year: startYear, Url: url.WikiUrl, Message: fmt.Sprintf("couldn't read vehicle list: %v", err)})
+				continue
+			}
+			result.Sources = append(result.Sources, source)
+
+			var inRange []VehicleLaunch
+			for _, launch := range parseVehicleList(response) {
+				if year := launch.Timestamp.Timestamp.Year(); year >= startYear && year <= endYear {
+					inRange = append(inRange, launch)
+				}
+			}
+
+			unmatched := mergeVehicleLaunches(list, result.Data.OrbitalFlights, inRange)
+			fmt.Printf("Merged %d of %d launches from %s\n", len(inRange)-len(unmatched), len(inRange), url.WikiUrl)
+			for _, launch := range unmatched {
+				result.Diagnostics = append(result.Diagnostics, Diagnostic{
+					Year:    launch.Timestamp.Timestamp.Year(),
+					Url:     url.WikiUrl,
+					Message: fmt.Sprintf("%s launch of %s (flight %s) isn't in the yearly tables", launch.Timestamp.DateString(), list.Name, launch.FlightNumber),
+				})
+			}
+		}
+	}
+}
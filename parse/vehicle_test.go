@@ -0,0 +1,78 @@
+package parse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var falconTables = [][][]string{
+	{
+		{"Rocket", "Launches", "Successes"},
+		{"Falcon 9", "2", "2"},
+	},
+	{
+		{"Flight No.", "Date and time (UTC)", "Version,booster[b]", "Launch site", "Payload[c]", "Payload mass", "Orbit", "Customer", "Launch outcome", "Booster landing"},
+		{"197", "3 January 2023,14:56[3]", "F9 B5B1060.15", "CCSFS, SLC-40", "Transporter-6", "~5,000 kg", "SSO", "Various", "Success", "Success(LZ-1)"},
+		{"Dedicated rideshare mission", "Dedicated rideshare mission", "Dedicated rideshare mission", "Dedicated rideshare mission", "Dedicated rideshare mission", "Dedicated rideshare mission", "Dedicated rideshare mission", "Dedicated rideshare mission", "Dedicated rideshare mission", "Dedicated rideshare mission"},
+		{"FH 5", "15 January 2023,22:56", "Falcon HeavyB1070B1064.4B1065.4", "KSC, LC-39A", "USSF-67", "~3,750 kg", "GEO", "USSF", "Success", "No attempt Success(LZ-1) Success(LZ-2)"},
+		{"198", "TBD", "F9 B5", "CCSFS", "Starlink", "", "LEO", "SpaceX", "Planned", ""},
+	},
+}
+
+func TestParseVehicleList(t *testing.T) {
+	got := parseVehicleList(falconTables)
+	require.Len(t, got, 2)
+
+	assert.Equal(t, "197", got[0].FlightNumber)
+	assert.Equal(t, time.Date(2023, 1, 3, 14, 56, 0, 0, time.UTC), got[0].Timestamp.Timestamp)
+	assert.Equal(t, "Transporter-6", got[0].Payload)
+	assert.Equal(t, "~5,000 kg", got[0].PayloadMass)
+	assert.Equal(t, "Various", got[0].Customer)
+	assert.Equal(t, []BoosterData{{Serial: "B1060", Flight: 15, LandingType: "LZ-1", LandingOutcome: "Success"}}, got[0].Boosters)
+
+	assert.Equal(t, []BoosterData{
+		{Serial: "B1070", LandingOutcome: "No attempt"},
+		{Serial: "B1064", Flight: 4, LandingType: "LZ-1", LandingOutcome: "Success"},
+		{Serial: "B1065", Flight: 4, LandingType: "LZ-2", LandingOutcome: "Success"},
+	}, got[1].Boosters)
+}
+
+func TestMergeVehicleLaunches(t *testing.T) {
+	launch := func(rocket string, day int, hour int) RocketData {
+		return RocketData{
+			Rocket:    rocket,
+			Timestamp: TimeData{Timestamp: time.Date(2023, 1, day, hour, 0, 0, 0, time.UTC), ParsedOk: true},
+		}
+	}
+	launches := []RocketData{
+		launch("Long March 2D", 3, 15),
+		launch("Falcon 9 Block 5", 3, 15),
+		launch("Falcon Heavy", 15, 23),
+	}
+
+	vehicleLaunches := parseVehicleList(falconTables)
+	vehicleLaunches = append(vehicleLaunches, VehicleLaunch{
+		FlightNumber: "199",
+		Timestamp:    TimeData{Timestamp: time.Date(2023, 1, 20, 0, 0, 0, 0, time.UTC), ParsedOk: true},
+	})
+
+	unmatched := mergeVehicleLaunches(VehicleLists[0], launches, vehicleLaunches)
+
+	assert.Empty(t, launches[0].Boosters)
+	assert.Equal(t, "B1060", launches[1].Boosters[0].Serial)
+	assert.Equal(t, "Various", launches[1].Customer)
+	assert.Len(t, launches[2].Boosters, 3)
+	require.Len(t, unmatched, 1)
+	assert.Equal(t, "199", unmatched[0].FlightNumber)
+}
+
+func TestVehicleListPageCovers(t *testing.T) {
+	page := VehicleListPage{FirstYear: 2020, LastYear: 2022}
+	assert.True(t, page.covers(2019, 2020))
+	assert.True(t, page.covers(2022, 2024))
+	assert.False(t, page.covers(2023, 2024))
+	assert.True(t, VehicleListPage{FirstYear: 2023}.covers(2030, 2030))
+}
@@ -0,0 +1,220 @@
+// Package query runs SQL over the cached launches, loaded into an in-memory
+// database with the same tables as the sqlite export
+package query
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"launchdata/export"
+)
+
+const (
+	FormatTable = "table"
+	FormatCsv   = "csv"
+	FormatJson  = "json"
+)
+
+var Formats = []string{FormatTable, FormatCsv, FormatJson}
+
+type Database struct {
+	db *sql.DB
+}
+
+// Open loads launches into a new in-memory database
+func Open(launches export.Launches) (*Database, error) {
+	db, err := export.OpenDatabase(":memory:")
+	if err != nil {
+		return nil, err
+	}
+	if err := export.CreateDatabase(db, launches); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Database{db: db}, nil
+}
+
+func (d *Database) Close() error {
+	return d.db.Close()
+}
+
+// Tables lists the tables and views that can be queried
+func (d *Database) Tables() ([]string, error) {
+	result, err := d.Query("SELECT name FROM sqlite_master WHERE type IN ('table', 'view') ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	var tables []string
+	for _, row := range result.Rows {
+		tables = append(tables, fmt.Sprint(row[0]))
+	}
+	return tables, nil
+}
+
+// Schema is the SQL that created the tables and views
+func (d *Database) Schema() (string, error) {
+	result, err := d.Query("SELECT sql FROM sqlite_master WHERE type IN ('table', 'view') ORDER BY name")
+	if err != nil {
+		return "", err
+	}
+	var statements []string
+	for _, row := range result.Rows {
+		statements = append(statements, fmt.Sprint(row[0])+";")
+	}
+	return strings.Join(statements, "\n\n"), nil
+}
+
+type Result struct {
+	Columns []string
+	// Rows hold int64, float64, string or nil, as sqlite returned them
+	Rows [][]interface{}
+}
+
+// Query runs a single statement. Statements that don't return rows, like
+// CREATE VIEW, give an empty result.
+func (d *Database) Query(query string) (Result, error) {
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return Result{}, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{Columns: columns}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return Result{}, err
+		}
+		for i, value := range values {
+			if b, ok := value.([]byte); ok {
+				values[i] = string(b)
+			}
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	return result, rows.Err()
+}
+
+func formatValue(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+func (r Result) strings() [][]string {
+	var rows [][]string
+	for _, row := range r.Rows {
+		var formatted []string
+		for _, value := range row {
+			formatted = append(formatted, formatValue(value))
+		}
+		rows = append(rows, formatted)
+	}
+	return rows
+}
+
+// CheckFormat returns an error unless format is one of Formats
+func CheckFormat(format string) error {
+	for _, known := range Formats {
+		if format == known {
+			return nil
+		}
+	}
+	return unknownFormat(format)
+}
+
+func unknownFormat(format string) error {
+	return fmt.Errorf("unknown format %q, expected one of %s", format, strings.Join(Formats, ", "))
+}
+
+// Write writes the result as an aligned table, CSV or a JSON array of objects
+func (r Result) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatTable:
+		return r.writeTable(w)
+	case FormatCsv:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(r.Columns); err != nil {
+			return err
+		}
+		if err := writer.WriteAll(r.strings()); err != nil {
+			return err
+		}
+		return writer.Error()
+	case FormatJson:
+		return r.writeJson(w)
+	default:
+		return unknownFormat(format)
+	}
+}
+
+func (r Result) writeTable(w io.Writer) error {
+	if len(r.Columns) == 0 {
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(r.Columns, "\t"))
+	for _, row := range r.strings() {
+		for i := range row {
+			// Keep multi-line notes on their row
+			row[i] = strings.Join(strings.Fields(row[i]), " ")
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "(%d rows)\n", len(r.Rows))
+	return err
+}
+
+// writeJson writes each row as an object, with its keys in column order
+func (r Result) writeJson(w io.Writer) error {
+	var b bytes.Buffer
+	b.WriteString("[")
+	for i, row := range r.Rows {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n  {")
+		for j, value := range row {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			key, err := json.Marshal(r.Columns[j])
+			if err != nil {
+				return err
+			}
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			b.Write(key)
+			b.WriteString(": ")
+			b.Write(encoded)
+		}
+		b.WriteString("}")
+	}
+	if len(r.Rows) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString("]\n")
+	_, err := w.Write(b.Bytes())
+	return err
+}
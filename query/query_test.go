@@ -0,0 +1,103 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"launchdata/parse"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDatabase(t *testing.T) *Database {
+	t.Helper()
+	launches := []parse.RocketData{
+		{
+			Id:                    "falcon-9/f9-1",
+			Timestamp:             parse.TimeData{Timestamp: time.Date(2021, 1, 8, 2, 15, 0, 0, time.UTC), ParsedOk: true},
+			Rocket:                "Falcon 9",
+			LaunchServiceProvider: "SpaceX",
+			Notes:                 "First line\nsecond line",
+			Payload:               []parse.PayloadData{{Payload: "Türksat 5A", Outcome: "Operational"}},
+		},
+		{
+			Id:                    "falcon-9/f9-2",
+			Timestamp:             parse.TimeData{Timestamp: time.Date(2021, 1, 20, 13, 2, 0, 0, time.UTC), ParsedOk: true},
+			Rocket:                "Falcon 9",
+			LaunchServiceProvider: "SpaceX",
+		},
+		{
+			Id:                    "long-march-2d/2d-1",
+			Timestamp:             parse.TimeData{Timestamp: time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC), ParsedOk: true},
+			Rocket:                "Long March 2D",
+			LaunchServiceProvider: "CASC",
+		},
+	}
+
+	db, err := Open(func(add func(year int, launch parse.RocketData) error) error {
+		for _, launch := range launches {
+			if err := add(launch.Timestamp.Timestamp.Year(), launch); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestQuery(t *testing.T) {
+	db := testDatabase(t)
+
+	result, err := db.Query("SELECT provider, count(*) AS launches FROM launches_flat WHERE year = 2021 GROUP BY provider")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"provider", "launches"}, result.Columns)
+	assert.Equal(t, [][]interface{}{{"SpaceX", int64(2)}}, result.Rows)
+
+	_, err = db.Query("SELECT * FROM rockets")
+	assert.ErrorContains(t, err, "no such table")
+}
+
+func TestTables(t *testing.T) {
+	tables, err := testDatabase(t).Tables()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"launches", "launches_flat", "organizations", "payloads", "sites", "vehicles"}, tables)
+}
+
+func TestWrite(t *testing.T) {
+	result, err := testDatabase(t).Query("SELECT id, site, notes FROM launches_flat ORDER BY id LIMIT 2")
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, result.Write(&b, FormatTable))
+	assert.Equal(t, "id             site  notes\n"+
+		"falcon-9/f9-1        First line second line\n"+
+		"falcon-9/f9-2        \n"+
+		"(2 rows)\n", b.String())
+
+	b.Reset()
+	require.NoError(t, result.Write(&b, FormatCsv))
+	assert.Equal(t, "id,site,notes\nfalcon-9/f9-1,,\"First line\nsecond line\"\nfalcon-9/f9-2,,\n", b.String())
+
+	b.Reset()
+	require.NoError(t, result.Write(&b, FormatJson))
+	assert.Equal(t, `[
+  {"id": "falcon-9/f9-1", "site": null, "notes": "First line\nsecond line"},
+  {"id": "falcon-9/f9-2", "site": null, "notes": null}
+]
+`, b.String())
+	assert.True(t, json.Valid(b.Bytes()))
+
+	assert.Error(t, result.Write(&b, "xml"))
+}
+
+func TestCheckFormat(t *testing.T) {
+	for _, format := range Formats {
+		assert.NoError(t, CheckFormat(format))
+	}
+	assert.ErrorContains(t, CheckFormat("xml"), "expected one of table, csv, json")
+}
@@ -0,0 +1,299 @@
+// Package report summarises a year of launches as a short report, in
+// markdown or plain text, written from templates that can be replaced.
+package report
+
+import (
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"launchdata/parse"
+)
+
+const (
+	FormatMarkdown = "md"
+	FormatText     = "text"
+)
+
+var Formats = []string{FormatMarkdown, FormatText}
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+// topCount is how many providers, vehicles and sites are listed
+const topCount = 5
+
+// Count is the launches of a provider, vehicle or site
+type Count struct {
+	Name     string
+	Launches int
+	// Failures include partial failures
+	Failures int
+}
+
+// Launch is a launch as the report describes it
+type Launch struct {
+	Date     string
+	Vehicle  string
+	Provider string
+	Site     string
+	Outcome  parse.Outcome
+	Payloads []string
+	Notes    string
+}
+
+type Report struct {
+	Year      int
+	Generated time.Time
+
+	// Launches doesn't count planned launches, which are in Planned
+	Launches  int
+	Successes int
+	// Failures include partial failures, which are also in PartialFailures
+	Failures        int
+	PartialFailures int
+	Planned         int
+	// Known is whether any launch's outcome is known, so SuccessRate means
+	// something
+	Known       bool
+	SuccessRate float64
+
+	Providers []Count
+	Vehicles  []Count
+	Sites     []Count
+
+	// NotableFailures are the launches that failed, in full or in part
+	NotableFailures []Launch
+	// MaidenFlights are the first launches of vehicles that hadn't flown
+	// before
+	MaidenFlights []Launch
+}
+
+// Flown is the vehicles that have flown, to tell maiden flights apart. A
+// vehicle has flown once any configuration of it has, so a new upper stage or
+// a rename like "Proton-M/Briz-M" to "Proton-M" isn't a maiden flight.
+type Flown map[string]bool
+
+var punctuationRegex = regexp.MustCompile(`[^\pL\pN]+`)
+
+// vehicleKey identifies a vehicle however its name is punctuated, e.g.
+// "Soyuz-2-1v / Volga" and "Soyuz-2.1v" are the same rocket
+func vehicleKey(rocket string) string {
+	return punctuationRegex.ReplaceAllString(strings.ToLower(parse.BaseVehicle(rocket)), "")
+}
+
+// Add records launch's vehicle as flown, unless the launch hasn't happened
+func (f Flown) Add(launch parse.RocketData) {
+	if launch.IsSpurious() || launch.Outcome() == parse.OutcomePlanned {
+		return
+	}
+	if vehicle := vehicleKey(launch.Rocket); vehicle != "" {
+		f[vehicle] = true
+	}
+}
+
+// Maiden is whether launch is the first flight of its vehicle
+func (f Flown) Maiden(launch parse.RocketData) bool {
+	vehicle := vehicleKey(launch.Rocket)
+	return vehicle != "" && !f[vehicle]
+}
+
+func describe(launch parse.RocketData) Launch {
+	described := Launch{
+		Date:     launch.Timestamp.DateString(),
+		Vehicle:  parse.NormaliseName(launch.Rocket),
+		Provider: parse.NormaliseName(launch.LaunchServiceProvider),
+		Site:     parse.NormaliseName(launch.LaunchSite),
+		Outcome:  launch.Outcome(),
+		Notes:    strings.TrimSpace(launch.Notes),
+	}
+	for _, payload := range launch.Payload {
+		if name := strings.Join(strings.Fields(payload.Payload), " "); name != "" {
+			described.Payloads = append(described.Payloads, name)
+		}
+	}
+	return described
+}
+
+type counter map[string]*Count
+
+func (c counter) add(name string, failed bool) {
+	if name == "" {
+		return
+	}
+	count, ok := c[name]
+	if !ok {
+		count = &Count{Name: name}
+		c[name] = count
+	}
+	count.Launches++
+	if failed {
+		count.Failures++
+	}
+}
+
+// top is the most launched, ties broken by name
+func (c counter) top() []Count {
+	var counts []Count
+	for _, count := range c {
+		counts = append(counts, *count)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Launches != counts[j].Launches {
+			return counts[i].Launches > counts[j].Launches
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	if len(counts) > topCount {
+		counts = counts[:topCount]
+	}
+	return counts
+}
+
+// Build reports on the launches cached under year. flown is the vehicles
+// that flew in earlier years, and has this year's added to it.
+func Build(year int, launches []parse.RocketData, flown Flown, generated time.Time) Report {
+	report := Report{Year: year, Generated: generated}
+	providers, vehicles, sites := counter{}, counter{}, counter{}
+
+	for _, launch := range launches {
+		if launch.IsSpurious() {
+			continue
+		}
+		outcome := launch.Outcome()
+		if outcome == parse.OutcomePlanned {
+			report.Planned++
+			continue
+		}
+
+		report.Launches++
+		failed := false
+		switch outcome {
+		case parse.OutcomeSuccess:
+			report.Successes++
+		case parse.OutcomePartialLaunchFailure:
+			report.PartialFailures++
+			failed = true
+		case parse.OutcomeLaunchFailure:
+			failed = true
+		}
+		described := describe(launch)
+		if failed {
+			report.Failures++
+			report.NotableFailures = append(report.NotableFailures, described)
+		}
+
+		providers.add(described.Provider, failed)
+		vehicles.add(described.Vehicle, failed)
+		sites.add(described.Site, failed)
+
+		if flown.Maiden(launch) {
+			report.MaidenFlights = append(report.MaidenFlights, described)
+		}
+		flown.Add(launch)
+	}
+
+	if known := report.Successes + report.Failures; known > 0 {
+		report.Known = true
+		report.SuccessRate = float64(report.Successes) / float64(known)
+	}
+	report.Providers = providers.top()
+	report.Vehicles = vehicles.top()
+	report.Sites = sites.top()
+	return report
+}
+
+var funcs = template.FuncMap{
+	"percent": func(rate float64) string {
+		return fmt.Sprintf("%.0f%%", rate*100)
+	},
+	"plural": func(n int, singular string, plural string) string {
+		if n == 1 {
+			return fmt.Sprintf("%d %s", n, singular)
+		}
+		return fmt.Sprintf("%d %s", n, plural)
+	},
+	"join": strings.Join,
+	// oneLine joins the lines of s, for notes in a list or table
+	"oneLine": func(s string) string {
+		return strings.Join(strings.Fields(s), " ")
+	},
+	// cell escapes s for a markdown table
+	"cell": func(s string) string {
+		return strings.ReplaceAll(strings.Join(strings.Fields(s), " "), "|", `\|`)
+	},
+	"pad": func(width int, s string) string {
+		return fmt.Sprintf("%-*s", width, s)
+	},
+	// dict makes a map of its key value pairs, to pass more than one value
+	// to a template
+	"dict": func(pairs ...interface{}) (map[string]interface{}, error) {
+		if len(pairs)%2 != 0 {
+			return nil, fmt.Errorf("dict needs pairs of keys and values")
+		}
+		dict := map[string]interface{}{}
+		for i := 0; i < len(pairs); i += 2 {
+			key, ok := pairs[i].(string)
+			if !ok {
+				return nil, fmt.Errorf("dict keys must be strings, found %v", pairs[i])
+			}
+			dict[key] = pairs[i+1]
+		}
+		return dict, nil
+	},
+	"date": func(t time.Time) string {
+		return t.Format("2006-01-02")
+	},
+}
+
+func templateName(format string) (string, error) {
+	switch format {
+	case FormatMarkdown:
+		return "report.md.tmpl", nil
+	case FormatText:
+		return "report.txt.tmpl", nil
+	}
+	return "", fmt.Errorf("unknown format %q, expected %s", format, strings.Join(Formats, " or "))
+}
+
+// Template is the built in template for format
+func Template(format string) (*template.Template, error) {
+	name, err := templateName(format)
+	if err != nil {
+		return nil, err
+	}
+	return template.New(name).Funcs(funcs).ParseFS(templateFiles, "templates/"+name)
+}
+
+// DefaultTemplate is the text of the built in template for format, to start
+// a replacement from
+func DefaultTemplate(format string) (string, error) {
+	name, err := templateName(format)
+	if err != nil {
+		return "", err
+	}
+	contents, err := templateFiles.ReadFile("templates/" + name)
+	return string(contents), err
+}
+
+// LoadTemplate reads a replacement template from filename. It's executed with
+// a Report, and can use the same functions as the built in templates.
+func LoadTemplate(filename string) (*template.Template, error) {
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return template.New(filepath.Base(filename)).Funcs(funcs).Parse(string(contents))
+}
+
+// Write writes report using tmpl
+func (r Report) Write(w io.Writer, tmpl *template.Template) error {
+	return tmpl.Execute(w, r)
+}
@@ -0,0 +1,179 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"launchdata/parse"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func launch(month time.Month, day int, rocket string, provider string, outcome string) parse.RocketData {
+	return parse.RocketData{
+		Timestamp:             parse.TimeData{Timestamp: time.Date(2022, month, day, 12, 0, 0, 0, time.UTC), ParsedOk: true},
+		Rocket:                rocket,
+		LaunchSite:            "Cape Canaveral SLC-40",
+		LaunchServiceProvider: provider,
+		Payload:               []parse.PayloadData{{Payload: rocket + " payload", Outcome: outcome}},
+	}
+}
+
+func testReport() Report {
+	failure := launch(time.February, 10, "Rocket 3.3", "Astra", "Launch failure")
+	failure.Notes = "The upper stage\nfailed | early."
+	launches := []parse.RocketData{
+		launch(time.January, 6, "Falcon 9", "SpaceX", "Successful"),
+		failure,
+		launch(time.March, 1, "Falcon  9", "SpaceX", "Operational"),
+		launch(time.April, 1, "New Glenn", "Blue Origin", "Planned"),
+		{Rocket: "\n\nMarch", Timestamp: parse.TimeData{TimestampRaw: "\n\nMarch"}},
+	}
+	flown := Flown{}
+	flown.Add(launch(time.May, 1, "Falcon 9", "SpaceX", "Successful"))
+	return Build(2022, launches, flown, time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC))
+}
+
+func TestBuild(t *testing.T) {
+	report := testReport()
+
+	assert.Equal(t, 3, report.Launches)
+	assert.Equal(t, 2, report.Successes)
+	assert.Equal(t, 1, report.Failures)
+	assert.Equal(t, 1, report.Planned)
+	assert.True(t, report.Known)
+	assert.InDelta(t, 2.0/3, report.SuccessRate, 0.001)
+	assert.Equal(t, []Count{{"SpaceX", 2, 0}, {"Astra", 1, 1}}, report.Providers)
+	assert.Equal(t, []Count{{"Falcon 9", 2, 0}, {"Rocket 3.3", 1, 1}}, report.Vehicles, "names are normalised")
+	assert.Equal(t, []Count{{"Cape Canaveral SLC-40", 3, 1}}, report.Sites)
+
+	require.Len(t, report.NotableFailures, 1)
+	assert.Equal(t, "Rocket 3.3", report.NotableFailures[0].Vehicle)
+	assert.Equal(t, []string{"Rocket 3.3 payload"}, report.NotableFailures[0].Payloads)
+
+	require.Len(t, report.MaidenFlights, 1, "Falcon 9 has flown before and New Glenn hasn't flown yet")
+	assert.Equal(t, "Rocket 3.3", report.MaidenFlights[0].Vehicle)
+}
+
+func TestFlownOnlyCountsTheFirstFlight(t *testing.T) {
+	report := Build(2022, []parse.RocketData{
+		launch(time.January, 1, "Ariane 6", "Arianespace", "Successful"),
+		launch(time.February, 1, "Ariane 6", "Arianespace", "Successful"),
+	}, Flown{}, time.Now())
+	require.Len(t, report.MaidenFlights, 1)
+	assert.Equal(t, "2022-01-01", report.MaidenFlights[0].Date)
+}
+
+func TestMaidenFlightsIgnoreConfigurationsAndSpelling(t *testing.T) {
+	flown := Flown{}
+	flown.Add(launch(time.January, 1, "Proton-M/Briz-M", "Khrunichev", "Successful"))
+	flown.Add(launch(time.January, 1, "Long March 2C", "CASC", "Successful"))
+	flown.Add(launch(time.January, 1, "Falcon 9 Block 5", "SpaceX", "Successful"))
+	flown.Add(launch(time.January, 1, "Soyuz-2-1v / Volga", "RVSN RF", "Successful"))
+	flown.Add(launch(time.January, 1, "Atlas V 401", "ULA", "Successful"))
+
+	report := Build(2022, []parse.RocketData{
+		launch(time.January, 6, "Proton-M", "Khrunichev", "Successful"),
+		launch(time.January, 7, "Long March 2C?", "CASC", "Successful"),
+		launch(time.January, 8, "Falcon 9 Block 5 / SHERPA-FX", "SpaceX", "Successful"),
+		launch(time.January, 9, "Soyuz-2.1v", "RVSN RF", "Successful"),
+		launch(time.January, 9, "Atlas V 511", "ULA", "Successful"),
+		launch(time.January, 10, "Angara A5/Persei", "RVSN RF", "Successful"),
+		launch(time.January, 11, "Angara A5 / Briz-M", "RVSN RF", "Successful"),
+	}, flown, time.Now())
+
+	require.Len(t, report.MaidenFlights, 1)
+	assert.Equal(t, "Angara A5/Persei", report.MaidenFlights[0].Vehicle)
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	tmpl, err := Template(FormatMarkdown)
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, testReport().Write(&b, tmpl))
+	assert.Equal(t, `# 2022 in orbital launches
+
+3 orbital launches: 2 successful and 1 failed. That's a 67% success rate. 1 more launch is planned.
+
+## Top providers
+
+| Provider | Launches | Failures |
+| --- | ---: | ---: |
+| SpaceX | 2 | 0 |
+| Astra | 1 | 1 |
+
+## Top vehicles
+
+| Vehicle | Launches | Failures |
+| --- | ---: | ---: |
+| Falcon 9 | 2 | 0 |
+| Rocket 3.3 | 1 | 1 |
+
+## Top launch sites
+
+| Site | Launches | Failures |
+| --- | ---: | ---: |
+| Cape Canaveral SLC-40 | 3 | 1 |
+
+## Notable failures
+
+- **2022-02-10 Rocket 3.3** carrying Rocket 3.3 payload (launch failure): The upper stage failed | early.
+
+## Maiden flights
+
+- **2022-02-10 Rocket 3.3** by Astra from Cape Canaveral SLC-40 (launch failure)
+
+_Generated by launchdata on 2023-01-02_
+`, b.String())
+}
+
+func TestWriteText(t *testing.T) {
+	tmpl, err := Template(FormatText)
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, testReport().Write(&b, tmpl))
+	assert.Contains(t, b.String(), "2022 in orbital launches\n\n3 orbital launches: 2 successful and 1 failed.")
+	assert.Contains(t, b.String(), "Top vehicles\n  Falcon 9                                 2 launches\n  Rocket 3.3                               1 launch, 1 failure\n")
+	assert.Contains(t, b.String(), "Notable failures\n  2022-02-10 Rocket 3.3 carrying Rocket 3.3 payload (launch failure)\n    The upper stage failed | early.\n")
+	assert.NotContains(t, b.String(), `\|`, "nothing is escaped for markdown")
+}
+
+func TestEmptyYear(t *testing.T) {
+	for _, format := range Formats {
+		tmpl, err := Template(format)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		require.NoError(t, Build(2030, nil, Flown{}, time.Now()).Write(&b, tmpl))
+		assert.Contains(t, b.String(), "0 orbital launches: 0 successful and 0 failed.\n")
+		assert.NotContains(t, b.String(), "Top providers")
+	}
+}
+
+func TestCustomTemplate(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "custom.tmpl")
+	require.NoError(t, os.WriteFile(filename, []byte(`{{.Year}}: {{percent .SuccessRate}}{{range .MaidenFlights}}, first {{.Vehicle}}{{end}}`), 0644))
+
+	tmpl, err := LoadTemplate(filename)
+	require.NoError(t, err)
+	var b bytes.Buffer
+	require.NoError(t, testReport().Write(&b, tmpl))
+	assert.Equal(t, "2022: 67%, first Rocket 3.3", b.String())
+
+	require.NoError(t, os.WriteFile(filename, []byte(`{{.Year`), 0644))
+	_, err = LoadTemplate(filename)
+	assert.Error(t, err)
+}
+
+func TestUnknownFormat(t *testing.T) {
+	_, err := Template("html")
+	assert.EqualError(t, err, `unknown format "html", expected md or text`)
+	_, err = DefaultTemplate("html")
+	assert.Error(t, err)
+}
@@ -0,0 +1,166 @@
+package satcat
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"launchdata/parse"
+)
+
+// Ambiguity is a payload that more than one catalog object could be. These
+// are usually batches of satellites listed as a single payload.
+type Ambiguity struct {
+	LaunchId   string
+	Payload    string
+	Candidates []string
+}
+
+type Report struct {
+	// Matched is the number of payloads given a NORAD id
+	Matched   int
+	Unmatched int
+	Ambiguous []Ambiguity
+}
+
+var nonAlnumRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+func normaliseName(name string) string {
+	return nonAlnumRegex.ReplaceAllString(strings.ToLower(name), "")
+}
+
+// namesMatch compares names loosely, since the catalog tends to add serial
+// numbers or drop suffixes, e.g. "Transporter-6" and "TRANSPORTER 6"
+func namesMatch(payload string, object string) bool {
+	a, b := normaliseName(payload), normaliseName(object)
+	if a == "" || b == "" {
+		return false
+	}
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+// launchObjects finds the catalog's payloads from launch. When the launch's
+// COSPAR id is known (see reconcile --enrich) it's used, otherwise any payload
+// launched the same day is a candidate.
+func launchObjects(launch parse.RocketData, byDate map[string][]Object) []Object {
+	var objects []Object
+	for _, object := range byDate[launchDate(launch)] {
+		if !object.Payload() {
+			continue
+		}
+		if launch.CosparId != "" && object.LaunchDesignator() != launch.CosparId {
+			continue
+		}
+		objects = append(objects, object)
+	}
+	return objects
+}
+
+func describe(object Object) string {
+	return fmt.Sprintf("%s (%d, %s)", object.Name, object.NoradId, object.IntlDesignator)
+}
+
+func launchDate(launch parse.RocketData) string {
+	return launch.Timestamp.Timestamp.Format("2006-01-02")
+}
+
+func failed(launch parse.RocketData) bool {
+	return launch.Outcome() == parse.OutcomeLaunchFailure
+}
+
+// onlyLaunchOf is whether launch is the only one that day the objects could
+// be from. They have to share a launch designator, and no other launch that
+// day can be known to have it or not be known at all.
+func onlyLaunchOf(launch *parse.RocketData, objects []Object, sameDay []*parse.RocketData) bool {
+	if len(objects) == 0 {
+		return false
+	}
+	designator := objects[0].LaunchDesignator()
+	for _, object := range objects[1:] {
+		if object.LaunchDesignator() != designator {
+			return false
+		}
+	}
+	for _, other := range sameDay {
+		if other != launch && (other.CosparId == "" || other.CosparId == designator) {
+			return false
+		}
+	}
+	return true
+}
+
+// Join fills in the catalog details of every payload in launches that can be
+// matched to exactly one catalog object, by launch date, international
+// designator and name. Failed launches are skipped, since nothing they
+// carried made it into the catalog.
+func Join(launches []parse.RocketData, objects []Object) Report {
+	byDate := map[string][]Object{}
+	for _, object := range objects {
+		byDate[object.LaunchDate] = append(byDate[object.LaunchDate], object)
+	}
+	launchesByDate := map[string][]*parse.RocketData{}
+	for i := range launches {
+		if launch := &launches[i]; launch.Timestamp.ParsedOk && !failed(*launch) {
+			launchesByDate[launchDate(*launch)] = append(launchesByDate[launchDate(*launch)], launch)
+		}
+	}
+
+	var report Report
+	// Each object can only be one payload
+	used := map[int]bool{}
+	for i := range launches {
+		launch := &launches[i]
+		if !launch.Timestamp.ParsedOk || failed(*launch) {
+			continue
+		}
+
+		var candidates []Object
+		for _, object := range launchObjects(*launch, byDate) {
+			if !used[object.NoradId] {
+				candidates = append(candidates, object)
+			}
+		}
+		for j := range launch.Payload {
+			payload := &launch.Payload[j]
+
+			var matches []Object
+			for _, object := range candidates {
+				if !used[object.NoradId] && namesMatch(payload.Payload, object.Name) {
+					matches = append(matches, object)
+				}
+			}
+			// A launch with a single payload could be any of the launch's
+			// objects, even if the names are nothing alike. There's often
+			// only one, and when there are more it's usually a batch. That's
+			// only safe when no other launch that day could own them,
+			// otherwise they're all reported as ambiguous.
+			ambiguous := len(matches) > 1
+			if len(matches) == 0 && len(launch.Payload) == 1 && len(candidates) > 0 {
+				matches = candidates
+				ambiguous = len(matches) > 1 || !onlyLaunchOf(launch, candidates, launchesByDate[launchDate(*launch)])
+			}
+
+			switch {
+			case len(matches) == 0:
+				report.Unmatched++
+			case !ambiguous:
+				object := matches[0]
+				used[object.NoradId] = true
+				payload.NoradId = object.NoradId
+				payload.IntlDesignator = object.IntlDesignator
+				payload.ObjectType = object.Type
+				payload.Status = object.Status()
+				payload.DecayDate = object.DecayDate
+				report.Matched++
+			default:
+				ambiguity := Ambiguity{LaunchId: launch.Id, Payload: strings.TrimSpace(payload.Payload)}
+				for _, object := range matches {
+					ambiguity.Candidates = append(ambiguity.Candidates, describe(object))
+				}
+				report.Ambiguous = append(report.Ambiguous, ambiguity)
+			}
+		}
+	}
+
+	return report
+}
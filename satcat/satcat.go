@@ -0,0 +1,124 @@
+// Package satcat reads a satellite catalog export, like CeleStrak's
+// satcat.csv, and joins its objects to the payloads we parsed, so each
+// payload can be linked to its NORAD catalog number.
+package satcat
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Object is a row of the catalog. Only the columns we use are kept.
+type Object struct {
+	Name string
+	// IntlDesignator is the COSPAR id of the object, e.g. "2023-001A"
+	IntlDesignator string
+	NoradId        int
+	// Type is PAY, R/B, DEB or UNK
+	Type       string
+	StatusCode string
+	LaunchDate string
+	DecayDate  string
+}
+
+func (o Object) Payload() bool {
+	return o.Type == "PAY"
+}
+
+// LaunchDesignator is the part of the international designator that names
+// the launch, e.g. "2023-001" for "2023-001A"
+func (o Object) LaunchDesignator() string {
+	if len(o.IntlDesignator) < 8 {
+		return o.IntlDesignator
+	}
+	return o.IntlDesignator[:8]
+}
+
+var statuses = map[string]string{
+	"+": "operational",
+	"-": "nonoperational",
+	"P": "partially operational",
+	"B": "backup",
+	"S": "spare",
+	"X": "extended mission",
+	"D": "decayed",
+	"?": "unknown",
+}
+
+// Status describes the object's operational status code
+func (o Object) Status() string {
+	if status, ok := statuses[o.StatusCode]; ok {
+		return status
+	}
+	if o.DecayDate != "" {
+		return "decayed"
+	}
+	return o.StatusCode
+}
+
+// Load reads a catalog in CeleStrak's CSV format
+func Load(filename string) ([]Object, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%s is empty", filename)
+	} else if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filename, err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{"OBJECT_NAME", "OBJECT_ID", "NORAD_CAT_ID", "LAUNCH_DATE"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%s doesn't look like a SATCAT export, it has no %s column", filename, name)
+		}
+	}
+
+	var objects []Object
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", filename, err)
+		}
+
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		noradId, err := strconv.Atoi(field("NORAD_CAT_ID"))
+		if err != nil {
+			line, _ := r.FieldPos(0)
+			return nil, fmt.Errorf("%s:%d: bad NORAD_CAT_ID %q", filename, line, field("NORAD_CAT_ID"))
+		}
+
+		objects = append(objects, Object{
+			Name:           field("OBJECT_NAME"),
+			IntlDesignator: field("OBJECT_ID"),
+			NoradId:        noradId,
+			Type:           field("OBJECT_TYPE"),
+			StatusCode:     field("OPS_STATUS_CODE"),
+			LaunchDate:     field("LAUNCH_DATE"),
+			DecayDate:      field("DECAY_DATE"),
+		})
+	}
+
+	return objects, nil
+}
@@ -0,0 +1,121 @@
+package satcat
+
+import (
+	"testing"
+	"time"
+
+	"launchdata/parse"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	objects, err := Load("testdata/satcat.csv")
+	require.NoError(t, err)
+	require.Len(t, objects, 5)
+
+	assert.Equal(t, Object{
+		Name:           "TRANSPORTER 6 DISPENSER",
+		IntlDesignator: "2023-001A",
+		NoradId:        55040,
+		Type:           "PAY",
+		StatusCode:     "+",
+		LaunchDate:     "2023-01-03",
+	}, objects[0])
+	assert.Equal(t, "2023-001", objects[0].LaunchDesignator())
+	assert.Equal(t, "operational", objects[0].Status())
+	assert.False(t, objects[3].Payload())
+	assert.Equal(t, "decayed", objects[3].Status())
+}
+
+func TestLoadRejectsOtherFiles(t *testing.T) {
+	_, err := Load("satcat.go")
+	assert.Error(t, err)
+}
+
+func TestJoin(t *testing.T) {
+	objects, err := Load("testdata/satcat.csv")
+	require.NoError(t, err)
+
+	launch := func(id string, day int, payloads ...string) parse.RocketData {
+		r := parse.RocketData{
+			Id:        id,
+			Timestamp: parse.TimeData{Timestamp: time.Date(2023, 1, day, 12, 0, 0, 0, time.UTC), ParsedOk: true},
+		}
+		for _, payload := range payloads {
+			r.Payload = append(r.Payload, parse.PayloadData{Payload: payload})
+		}
+		return r
+	}
+
+	launches := []parse.RocketData{
+		launch("falcon-9/transporter-6", 3, "Transporter-6", "Unknown cubesat"),
+		launch("falcon-9/starlink", 3, "Starlink Group 5-1"),
+		launch("long-march-7a/y6", 8, "TJS-3"),
+	}
+	// Knowing the launch rules out the Starlinks from the same day
+	launches[0].CosparId = "2023-001"
+
+	report := Join(launches, objects)
+
+	assert.Equal(t, 2, report.Matched)
+	assert.Equal(t, 1, report.Unmatched)
+
+	transporter := launches[0].Payload[0]
+	assert.Equal(t, 55040, transporter.NoradId)
+	assert.Equal(t, "2023-001A", transporter.IntlDesignator)
+	assert.Equal(t, "PAY", transporter.ObjectType)
+	assert.Equal(t, "operational", transporter.Status)
+	assert.Zero(t, launches[0].Payload[1].NoradId)
+
+	// The only payload on the only launch that day, even though the names
+	// are nothing alike
+	assert.Equal(t, 55131, launches[2].Payload[0].NoradId)
+
+	require.Len(t, report.Ambiguous, 1)
+	assert.Equal(t, "falcon-9/starlink", report.Ambiguous[0].LaunchId)
+	assert.Len(t, report.Ambiguous[0].Candidates, 2)
+	assert.Zero(t, launches[1].Payload[0].NoradId)
+}
+
+func TestJoinSameDayLaunches(t *testing.T) {
+	objects, err := Load("testdata/satcat.csv")
+	require.NoError(t, err)
+
+	launch := func(id string, outcome string) parse.RocketData {
+		return parse.RocketData{
+			Id:        id,
+			Timestamp: parse.TimeData{Timestamp: time.Date(2023, 1, 8, 12, 0, 0, 0, time.UTC), ParsedOk: true},
+			Payload:   []parse.PayloadData{{Payload: id + " payload", Outcome: outcome}},
+		}
+	}
+
+	// A failed launch carried nothing into the catalog, so it can't take the
+	// other launch's object however they're ordered
+	launches := []parse.RocketData{
+		launch("kinetica-1/failed", "Launch failure"),
+		launch("long-march-7a/y6", "Operational"),
+	}
+	report := Join(launches, objects)
+	assert.Equal(t, 1, report.Matched)
+	assert.Zero(t, launches[0].Payload[0].NoradId)
+	assert.Equal(t, 55131, launches[1].Payload[0].NoradId)
+
+	// With two launches that day, either could own the object
+	launches = []parse.RocketData{
+		launch("kinetica-1/ok", "Operational"),
+		launch("long-march-7a/y6", "Operational"),
+	}
+	report = Join(launches, objects)
+	assert.Zero(t, report.Matched)
+	require.Len(t, report.Ambiguous, 2)
+	assert.Equal(t, []string{"SHIJIAN-23 (55131, 2023-003A)"}, report.Ambiguous[0].Candidates)
+	assert.Zero(t, launches[0].Payload[0].NoradId)
+
+	// Unless the other launch is known to be a different one
+	launches[0].CosparId = "2023-004"
+	report = Join(launches, objects)
+	assert.Equal(t, 1, report.Matched)
+	assert.Equal(t, 55131, launches[1].Payload[0].NoradId)
+}
@@ -0,0 +1,119 @@
+// Package schema describes the cached data files as a JSON Schema, generated
+// from the Go types so the published schema can't drift from what we write.
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"launchdata/jsonio"
+	"launchdata/parse"
+)
+
+// Filename is where the published schema lives, relative to this package
+const Filename = "launchdata.schema.json"
+
+type object = map[string]interface{}
+
+type generator struct {
+	defs object
+}
+
+// Generate returns the JSON Schema for AllLaunchData
+func Generate() object {
+	g := generator{defs: object{}}
+	root := g.structSchema(reflect.TypeOf(parse.AllLaunchData{}))
+
+	// Older files have to be migrated before they'll match
+	properties := root["properties"].(object)
+	properties["SchemaVersion"] = object{"type": "integer", "const": parse.SchemaVersion}
+
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["title"] = "launchdata"
+	root["description"] = "Launches parsed from wikipedia's launch tables, one file per year"
+	root["$defs"] = g.defs
+	return root
+}
+
+// Bytes returns the formatted schema, as it's published
+func Bytes() ([]byte, error) {
+	formatted, err := jsonio.FormattedJson(Generate())
+	if err != nil {
+		return nil, err
+	}
+	return append(formatted.Bytes(), '\n'), nil
+}
+
+func (g generator) typeSchema(t reflect.Type) object {
+	if t == reflect.TypeOf(time.Time{}) {
+		return object{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return object{"type": "string"}
+	case reflect.Bool:
+		return object{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return object{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return object{"type": "number"}
+	case reflect.Ptr:
+		return object{"anyOf": []object{g.typeSchema(t.Elem()), {"type": "null"}}}
+	case reflect.Slice:
+		// nil slices are written as null
+		return object{"type": []string{"array", "null"}, "items": g.typeSchema(t.Elem())}
+	case reflect.Map:
+		return object{"type": "object", "additionalProperties": g.typeSchema(t.Elem())}
+	case reflect.Struct:
+		if _, ok := g.defs[t.Name()]; !ok {
+			// Reserve the name first in case the type refers to itself
+			g.defs[t.Name()] = object{}
+			g.defs[t.Name()] = g.structSchema(t)
+		}
+		return object{"$ref": "#/$defs/" + t.Name()}
+	}
+
+	return object{}
+}
+
+func (g generator) structSchema(t reflect.Type) object {
+	properties := object{}
+	required := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		omitempty := false
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			parts := strings.Split(tag, ",")
+			if parts[0] == "-" {
+				continue
+			}
+			if parts[0] != "" {
+				name = parts[0]
+			}
+			for _, option := range parts[1:] {
+				omitempty = omitempty || option == "omitempty"
+			}
+		}
+
+		properties[name] = g.typeSchema(field.Type)
+		if !omitempty {
+			required = append(required, name)
+		}
+	}
+
+	return object{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
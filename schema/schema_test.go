@@ -0,0 +1,24 @@
+package schema
+
+import (
+	"os"
+	"testing"
+
+	golden "github.com/jimeh/go-golden"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The published schema is checked in, regenerate it with `just test-accept`
+func TestPublishedSchemaIsUpToDate(t *testing.T) {
+	got, err := Bytes()
+	require.NoError(t, err)
+
+	if golden.Update() {
+		require.NoError(t, os.WriteFile(Filename, got, 0o644))
+	}
+
+	want, err := os.ReadFile(Filename)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got), "%s is out of date, regenerate it with GOLDEN_UPDATE=true go test ./schema", Filename)
+}
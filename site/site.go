@@ -0,0 +1,429 @@
+// Package site renders the cached launches as a static website, with a page
+// for each year, vehicle, launch site and provider. The pages are plain HTML
+// and CSS, so they can be served from anywhere.
+package site
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"launchdata/config"
+	"launchdata/parse"
+)
+
+//go:embed templates/*.html
+var templateFiles embed.FS
+
+//go:embed style.css
+var stylesheet []byte
+
+// Launch is a launch and the year it's cached under
+type Launch struct {
+	Year   int
+	Launch parse.RocketData
+}
+
+type Stats struct {
+	// Launches doesn't count planned launches
+	Launches  int
+	Successes int
+	// Failures include partial failures
+	Failures int
+	// Known is whether any launch's outcome is known, so SuccessRate means
+	// something
+	Known       bool
+	SuccessRate float64
+	First       string
+	Last        string
+}
+
+func (s *Stats) add(launch parse.RocketData) {
+	outcome := launch.Outcome()
+	if outcome == parse.OutcomePlanned {
+		return
+	}
+	s.Launches++
+	switch outcome {
+	case parse.OutcomeSuccess:
+		s.Successes++
+	case parse.OutcomeLaunchFailure, parse.OutcomePartialLaunchFailure:
+		s.Failures++
+	}
+	if known := s.Successes + s.Failures; known > 0 {
+		s.Known = true
+		s.SuccessRate = float64(s.Successes) / float64(known)
+	}
+
+	if launch.Timestamp.ParsedOk {
+		date := launch.Timestamp.DateString()
+		if s.First == "" || date < s.First {
+			s.First = date
+		}
+		if date > s.Last {
+			s.Last = date
+		}
+	}
+}
+
+// link is a name linking to its page, if it has one. Hrefs are relative to
+// the pages one directory down, which is every page but the index.
+type link struct {
+	Name string
+	Href string
+}
+
+type launchView struct {
+	Year         int
+	Anchor       string
+	Time         string
+	Vehicle      link
+	Site         link
+	Provider     link
+	FlightNumber string
+	Outcome      parse.Outcome
+	Payloads     []parse.PayloadData
+	PayloadNames string
+	Notes        string
+}
+
+type count struct {
+	Name     string
+	Href     string
+	Launches int
+}
+
+type related struct {
+	Kind   string
+	Counts []count
+}
+
+type group struct {
+	Name     string
+	Slug     string
+	Stats    Stats
+	Launches []launchView
+	Related  []related
+}
+
+// kind is a way of grouping launches, each group getting its own page
+type kind struct {
+	Title string
+	Dir   string
+	Name  func(launch launchView) link
+
+	groups map[string]*group
+	slugs  *slugs
+}
+
+type yearSummary struct {
+	Year     int
+	Stats    Stats
+	Launches []launchView
+}
+
+var slugRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugs turns names into file names, making sure no two names share one
+type slugs struct {
+	taken map[string]bool
+}
+
+func (s *slugs) slug(name string) string {
+	base := strings.Trim(slugRegex.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if base == "" {
+		base = "unnamed"
+	}
+	slug := base
+	for n := 2; s.taken[slug]; n++ {
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+	s.taken[slug] = true
+	return slug
+}
+
+func newSlugs() *slugs {
+	return &slugs{taken: map[string]bool{}}
+}
+
+func launchTime(t parse.TimeData) string {
+	if t.ParsedOk && !strings.Contains(t.TimestampClean, ":") {
+		return t.DateString()
+	}
+	return strings.TrimSpace(t.TimeString())
+}
+
+func cleanPayloads(payloads []parse.PayloadData) ([]parse.PayloadData, string) {
+	var cleaned []parse.PayloadData
+	var names []string
+	for _, payload := range payloads {
+		payload.Payload = strings.TrimSpace(payload.Payload)
+		payload.Operator = strings.TrimSpace(payload.Operator)
+		payload.Orbit = strings.TrimSpace(payload.Orbit)
+		payload.Function = strings.TrimSpace(payload.Function)
+		payload.Outcome = strings.TrimSpace(payload.Outcome)
+		cleaned = append(cleaned, payload)
+		if payload.Payload != "" {
+			names = append(names, payload.Payload)
+		}
+	}
+	return cleaned, strings.Join(names, ", ")
+}
+
+type Site struct {
+	Generated time.Time
+	Stats     Stats
+	years     []*yearSummary
+	kinds     []*kind
+}
+
+// Build groups launches into the site's pages. Rows that aren't really
+// launches are left out.
+func Build(launches []Launch, generated time.Time) *Site {
+	site := &Site{Generated: generated}
+	vehicles := &kind{Title: "Vehicles", Dir: "vehicles", Name: func(l launchView) link { return l.Vehicle }}
+	sites := &kind{Title: "Sites", Dir: "sites", Name: func(l launchView) link { return l.Site }}
+	providers := &kind{Title: "Providers", Dir: "providers", Name: func(l launchView) link { return l.Provider }}
+	site.kinds = []*kind{vehicles, sites, providers}
+	for _, k := range site.kinds {
+		k.groups = map[string]*group{}
+		k.slugs = newSlugs()
+	}
+
+	linkTo := func(k *kind, name string) link {
+		// The same name is written with different spaces now and then
+		name = parse.NormaliseName(name)
+		if name == "" {
+			return link{}
+		}
+		g, ok := k.groups[name]
+		if !ok {
+			g = &group{Name: name, Slug: k.slugs.slug(name)}
+			k.groups[name] = g
+		}
+		return link{Name: name, Href: fmt.Sprintf("../%s/%s.html", k.Dir, g.Slug)}
+	}
+
+	byYear := map[int]*yearSummary{}
+	anchors := map[int]*slugs{}
+	for _, l := range launches {
+		launch := l.Launch
+		if launch.IsSpurious() {
+			continue
+		}
+
+		year, ok := byYear[l.Year]
+		if !ok {
+			year = &yearSummary{Year: l.Year}
+			byYear[l.Year] = year
+			anchors[l.Year] = newSlugs()
+			site.years = append(site.years, year)
+		}
+
+		payloads, payloadNames := cleanPayloads(launch.Payload)
+		view := launchView{
+			Year:         l.Year,
+			Anchor:       anchors[l.Year].slug(launch.Id),
+			Time:         launchTime(launch.Timestamp),
+			Vehicle:      linkTo(vehicles, launch.Rocket),
+			Site:         linkTo(sites, launch.LaunchSite),
+			Provider:     linkTo(providers, launch.LaunchServiceProvider),
+			FlightNumber: strings.TrimSpace(launch.FlightNumber),
+			Outcome:      launch.Outcome(),
+			Payloads:     payloads,
+			PayloadNames: payloadNames,
+			Notes:        strings.TrimSpace(launch.Notes),
+		}
+
+		year.Launches = append(year.Launches, view)
+		year.Stats.add(launch)
+		site.Stats.add(launch)
+		for _, k := range site.kinds {
+			if name := k.Name(view).Name; name != "" {
+				g := k.groups[name]
+				g.Launches = append(g.Launches, view)
+				g.Stats.add(launch)
+			}
+		}
+	}
+	sort.Slice(site.years, func(i, j int) bool { return site.years[i].Year < site.years[j].Year })
+
+	// Each group links to the others its launches share, e.g. a vehicle's
+	// sites and providers
+	for _, k := range site.kinds {
+		for _, g := range k.groups {
+			for _, other := range site.kinds {
+				if other != k {
+					g.Related = append(g.Related, related{Kind: other.Title, Counts: topCounts(g.Launches, other.Name, 10)})
+				}
+			}
+		}
+	}
+
+	return site
+}
+
+func topCounts(launches []launchView, name func(launchView) link, limit int) []count {
+	counts := map[link]int{}
+	for _, launch := range launches {
+		if l := name(launch); l.Name != "" {
+			counts[l]++
+		}
+	}
+
+	var top []count
+	for l, n := range counts {
+		top = append(top, count{Name: l.Name, Href: l.Href, Launches: n})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Launches != top[j].Launches {
+			return top[i].Launches > top[j].Launches
+		}
+		return top[i].Name < top[j].Name
+	})
+	if len(top) > limit {
+		top = top[:limit]
+	}
+	return top
+}
+
+func (k *kind) sortedGroups() []*group {
+	var groups []*group
+	for _, g := range k.groups {
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Stats.Launches != groups[j].Stats.Launches {
+			return groups[i].Stats.Launches > groups[j].Stats.Launches
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
+
+// document is what every template is executed with
+type document struct {
+	Title     string
+	Root      string
+	Generated string
+	Page      interface{}
+}
+
+var funcs = template.FuncMap{
+	"percent": func(rate float64) string {
+		return fmt.Sprintf("%.0f%%", rate*100)
+	},
+	"outcomeClass": func(outcome parse.Outcome) string {
+		switch outcome {
+		case parse.OutcomeSuccess:
+			return "success"
+		case parse.OutcomeLaunchFailure, parse.OutcomePartialLaunchFailure:
+			return "failure"
+		}
+		return ""
+	},
+}
+
+func pageTemplate(name string) *template.Template {
+	return template.Must(template.New(name).Funcs(funcs).ParseFS(templateFiles, "templates/layout.html", "templates/"+name))
+}
+
+var (
+	indexTemplate  = pageTemplate("index.html")
+	yearTemplate   = pageTemplate("year.html")
+	groupsTemplate = pageTemplate("groups.html")
+	groupTemplate  = pageTemplate("group.html")
+)
+
+type writer struct {
+	config    config.Config
+	dir       string
+	generated string
+	pages     int
+}
+
+func (w *writer) write(filename string, contents []byte) error {
+	w.pages++
+	if w.config.DryRun {
+		return nil
+	}
+	filename = filepath.Join(w.dir, filename)
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, contents, 0o644)
+}
+
+func (w *writer) render(filename string, tmpl *template.Template, title string, page interface{}) error {
+	root := "../"
+	if !strings.Contains(filename, "/") {
+		root = ""
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&b, "layout", document{Title: title, Root: root, Generated: w.generated, Page: page}); err != nil {
+		return fmt.Errorf("rendering %s: %w", filename, err)
+	}
+	return w.write(filename, b.Bytes())
+}
+
+// Write writes every page of the site into dir, returning how many files
+// it wrote
+func (s *Site) Write(config config.Config, dir string) (int, error) {
+	w := &writer{config: config, dir: dir, generated: s.Generated.UTC().Format("2006-01-02 15:04 (UTC)")}
+
+	if err := w.write("style.css", stylesheet); err != nil {
+		return w.pages, err
+	}
+
+	index := struct {
+		Stats Stats
+		Years []*yearSummary
+	}{s.Stats, s.years}
+	if err := w.render("index.html", indexTemplate, "Orbital launches", index); err != nil {
+		return w.pages, err
+	}
+
+	for i, year := range s.years {
+		page := struct {
+			Stats          Stats
+			Previous, Next int
+			Launches       []launchView
+		}{Stats: year.Stats, Launches: year.Launches}
+		if i > 0 {
+			page.Previous = s.years[i-1].Year
+		}
+		if i < len(s.years)-1 {
+			page.Next = s.years[i+1].Year
+		}
+		if err := w.render(fmt.Sprintf("years/%d.html", year.Year), yearTemplate, fmt.Sprint(year.Year), page); err != nil {
+			return w.pages, err
+		}
+	}
+
+	for _, k := range s.kinds {
+		groups := k.sortedGroups()
+		page := struct {
+			Kind   string
+			Groups []*group
+		}{strings.TrimSuffix(k.Title, "s"), groups}
+		if err := w.render(k.Dir+"/index.html", groupsTemplate, k.Title, page); err != nil {
+			return w.pages, err
+		}
+
+		for _, g := range groups {
+			if err := w.render(fmt.Sprintf("%s/%s.html", k.Dir, g.Slug), groupTemplate, g.Name, g); err != nil {
+				return w.pages, err
+			}
+		}
+	}
+
+	return w.pages, nil
+}
@@ -0,0 +1,139 @@
+package site
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"launchdata/config"
+	"launchdata/parse"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLaunches() []Launch {
+	launch := func(year int, month time.Month, day int, rocket string, site string, provider string, outcome string) Launch {
+		return Launch{Year: year, Launch: parse.RocketData{
+			Id:                    rocket + "/" + time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Format("2006-01-02"),
+			Timestamp:             parse.TimeData{TimestampClean: "x 12:00", Timestamp: time.Date(year, month, day, 12, 0, 0, 0, time.UTC), ParsedOk: true},
+			Rocket:                rocket,
+			LaunchSite:            site,
+			LaunchServiceProvider: provider,
+			Payload:               []parse.PayloadData{{Payload: rocket + " payload", Outcome: outcome}},
+		}}
+	}
+
+	launches := []Launch{
+		launch(2021, time.March, 1, "Falcon 9", "Kennedy LC-39A", "SpaceX", "Successful"),
+		launch(2022, time.January, 6, "Falcon 9", "Cape Canaveral SLC-40", "SpaceX", "Successful"),
+		launch(2022, time.February, 10, "Rocket 3.3", "Cape Canaveral SLC-46", "Astra", "Launch failure"),
+		{Year: 2022, Launch: parse.RocketData{Rocket: "\n\nMarch", Timestamp: parse.TimeData{TimestampRaw: "\n\nMarch"}}},
+	}
+	launches[1].Launch.Notes = "Booster <b>landed</b>"
+	return launches
+}
+
+func TestBuild(t *testing.T) {
+	site := Build(testLaunches(), time.Now())
+
+	assert.Equal(t, Stats{Launches: 3, Successes: 2, Failures: 1, Known: true, SuccessRate: 2.0 / 3, First: "2021-03-01", Last: "2022-02-10"}, site.Stats)
+	require.Len(t, site.years, 2)
+	assert.Len(t, site.years[1].Launches, 2, "the month heading isn't a launch")
+
+	vehicles := site.kinds[0].sortedGroups()
+	require.Len(t, vehicles, 2)
+	falcon := vehicles[0]
+	assert.Equal(t, "Falcon 9", falcon.Name)
+	assert.Equal(t, "falcon-9", falcon.Slug)
+	assert.Equal(t, 2, falcon.Stats.Launches)
+	assert.Equal(t, []related{
+		{Kind: "Sites", Counts: []count{
+			{Name: "Cape Canaveral SLC-40", Href: "../sites/cape-canaveral-slc-40.html", Launches: 1},
+			{Name: "Kennedy LC-39A", Href: "../sites/kennedy-lc-39a.html", Launches: 1},
+		}},
+		{Kind: "Providers", Counts: []count{{Name: "SpaceX", Href: "../providers/spacex.html", Launches: 2}}},
+	}, falcon.Related)
+}
+
+func TestBuildGroupsNamesSpacedDifferently(t *testing.T) {
+	launches := testLaunches()[:2]
+	launches[0].Launch.Rocket = "Proton-M/Briz-M"
+	launches[1].Launch.Rocket = "Proton-M /  Briz-M"
+	site := Build(launches, time.Now())
+
+	vehicles := site.kinds[0].sortedGroups()
+	require.Len(t, vehicles, 1)
+	assert.Equal(t, "Proton-M/Briz-M", vehicles[0].Name)
+	assert.Equal(t, "proton-m-briz-m", vehicles[0].Slug)
+	assert.Equal(t, 2, vehicles[0].Stats.Launches)
+}
+
+func TestSlugsAreUnique(t *testing.T) {
+	s := newSlugs()
+	assert.Equal(t, "soyuz-2-1a", s.slug("Soyuz-2.1a"))
+	assert.Equal(t, "soyuz-2-1a-2", s.slug("Soyuz 2.1a"))
+	assert.Equal(t, "unnamed", s.slug("长征"))
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	pages, err := Build(testLaunches(), time.Date(2022, 3, 1, 9, 0, 0, 0, time.UTC)).Write(config.Config{}, dir)
+	require.NoError(t, err)
+
+	var files []string
+	require.NoError(t, filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			rel, _ := filepath.Rel(dir, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return err
+	}))
+	assert.ElementsMatch(t, []string{
+		"style.css",
+		"index.html",
+		"years/2021.html",
+		"years/2022.html",
+		"vehicles/index.html",
+		"vehicles/falcon-9.html",
+		"vehicles/rocket-3-3.html",
+		"sites/index.html",
+		"sites/kennedy-lc-39a.html",
+		"sites/cape-canaveral-slc-40.html",
+		"sites/cape-canaveral-slc-46.html",
+		"providers/index.html",
+		"providers/spacex.html",
+		"providers/astra.html",
+	}, files)
+	assert.Equal(t, len(files), pages)
+
+	index, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(index), `<link rel="stylesheet" href="style.css">`)
+	assert.Contains(t, string(index), `<a href="years/2022.html">2022</a>`)
+	assert.Contains(t, string(index), "Generated 2022-03-01 09:00 (UTC)")
+	assert.NotContains(t, string(index), "<script")
+
+	year, err := os.ReadFile(filepath.Join(dir, "years", "2022.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(year), `<link rel="stylesheet" href="../style.css">`)
+	assert.Contains(t, string(year), `<a href="../vehicles/rocket-3-3.html">Rocket 3.3</a>`)
+	assert.Contains(t, string(year), `<span class="outcome failure">launch failure</span>`)
+	assert.Contains(t, string(year), `<td>Rocket 3.3 payload</td>`)
+	assert.Contains(t, string(year), "Booster &lt;b&gt;landed&lt;/b&gt;", "notes are escaped")
+	assert.Contains(t, string(year), `<a href="2021.html">← 2021</a>`)
+
+	vehicle, err := os.ReadFile(filepath.Join(dir, "vehicles", "falcon-9.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(vehicle), "2 launches · 2 successes · 0 failures · 100% success rate")
+	assert.Contains(t, string(vehicle), `<a href="../years/2021.html#falcon-9-2021-03-01">`)
+}
+
+func TestWriteDryRun(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "public")
+	pages, err := Build(testLaunches(), time.Now()).Write(config.Config{DryRun: true}, dir)
+	require.NoError(t, err)
+	assert.Equal(t, 14, pages)
+	assert.NoDirExists(t, dir)
+}
@@ -0,0 +1,240 @@
+// Package sites places launch sites on a map, using a gazetteer of where
+// each spaceport is, and summarises the launches from each of them.
+package sites
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"launchdata/parse"
+)
+
+//go:embed gazetteer.csv
+var embeddedGazetteer string
+
+// Site is a spaceport in the gazetteer
+type Site struct {
+	Name      string
+	Country   string
+	Latitude  float64
+	Longitude float64
+	// Aliases are the names that launch sites from this spaceport start with
+	Aliases []string
+}
+
+type Gazetteer struct {
+	Sites []Site
+}
+
+// Default returns the embedded gazetteer from gazetteer.csv
+func Default() (Gazetteer, error) {
+	return readGazetteer(strings.NewReader(embeddedGazetteer))
+}
+
+// Load reads a gazetteer from a CSV file with the same layout as
+// gazetteer.csv. Lines starting with # are ignored.
+func Load(filename string) (Gazetteer, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return Gazetteer{}, err
+	}
+	defer f.Close()
+
+	g, err := readGazetteer(f)
+	if err != nil {
+		return Gazetteer{}, fmt.Errorf("%s: %w", filename, err)
+	}
+	return g, nil
+}
+
+func readGazetteer(r io.Reader) (Gazetteer, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 5
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return Gazetteer{}, err
+	}
+	if len(records) == 0 {
+		return Gazetteer{}, fmt.Errorf("no sites")
+	}
+
+	var g Gazetteer
+	// The first record is the header
+	for _, record := range records[1:] {
+		name := strings.TrimSpace(record[0])
+		latitude, err := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+		if err != nil || latitude < -90 || latitude > 90 {
+			return Gazetteer{}, fmt.Errorf("bad latitude %q for %s", record[2], name)
+		}
+		longitude, err := strconv.ParseFloat(strings.TrimSpace(record[3]), 64)
+		if err != nil || longitude < -180 || longitude > 180 {
+			return Gazetteer{}, fmt.Errorf("bad longitude %q for %s", record[3], name)
+		}
+
+		site := Site{Name: name, Country: strings.TrimSpace(record[1]), Latitude: latitude, Longitude: longitude}
+		for _, alias := range strings.Split(record[4], "|") {
+			if alias = strings.TrimSpace(alias); alias != "" {
+				site.Aliases = append(site.Aliases, alias)
+			}
+		}
+		if len(site.Aliases) == 0 {
+			return Gazetteer{}, fmt.Errorf("no aliases for %s", name)
+		}
+		g.Sites = append(g.Sites, site)
+	}
+	return g, nil
+}
+
+func normaliseName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// startsWithWord is whether s starts with prefix followed by the end of s or
+// anything other than a letter, so "MARS LP-0A" is at MARS but "Marshall"
+// isn't
+func startsWithWord(s string, prefix string) bool {
+	if !strings.HasPrefix(s, prefix) {
+		return false
+	}
+	next, _ := utf8.DecodeRuneInString(s[len(prefix):])
+	return next == utf8.RuneError || !unicode.IsLetter(next)
+}
+
+// Locate finds the spaceport a launch site is at, e.g. "Baikonur Site 1/5" is
+// at Baikonur Cosmodrome. The longest matching alias wins.
+func (g Gazetteer) Locate(launchSite string) (Site, bool) {
+	name := normaliseName(launchSite)
+
+	var best Site
+	bestLength := 0
+	for _, site := range g.Sites {
+		for _, alias := range site.Aliases {
+			alias = normaliseName(alias)
+			if len(alias) > bestLength && startsWithWord(name, alias) {
+				best, bestLength = site, len(alias)
+			}
+		}
+	}
+	return best, bestLength > 0
+}
+
+type VehicleCount struct {
+	Vehicle  string
+	Launches int
+}
+
+// Stats summarises the launches from a spaceport
+type Stats struct {
+	Site
+	Launches  int
+	Successes int
+	// Failures include partial failures
+	Failures int
+	// SuccessRate is the share of the launches with a known outcome that
+	// succeeded
+	SuccessRate float64
+	FirstLaunch string
+	LastLaunch  string
+	// TopVehicles are the most launched vehicles, at most three
+	TopVehicles []VehicleCount
+}
+
+// LocatedLaunch is a launch and where it was launched from
+type LocatedLaunch struct {
+	Launch parse.RocketData
+	Site   Site
+}
+
+type Summary struct {
+	Sites    []Stats
+	Launches []LocatedLaunch
+	// Unlocated are the launch sites not in the gazetteer, with their number
+	// of launches. Most are ships and aircraft.
+	Unlocated map[string]int
+}
+
+// Summarise places each launch that has happened at its spaceport. Sites are
+// ordered by number of launches, most first.
+func (g Gazetteer) Summarise(launches []parse.RocketData) Summary {
+	summary := Summary{Unlocated: map[string]int{}}
+	stats := map[string]*Stats{}
+	vehicles := map[string]map[string]int{}
+
+	for _, launch := range launches {
+		outcome := launch.Outcome()
+		if outcome == parse.OutcomePlanned || !launch.Timestamp.ParsedOk || launch.IsSpurious() {
+			continue
+		}
+		site, ok := g.Locate(launch.LaunchSite)
+		if !ok {
+			if name := strings.TrimSpace(launch.LaunchSite); name != "" {
+				summary.Unlocated[name]++
+			}
+			continue
+		}
+		summary.Launches = append(summary.Launches, LocatedLaunch{launch, site})
+
+		s, ok := stats[site.Name]
+		if !ok {
+			s = &Stats{Site: site}
+			stats[site.Name] = s
+			vehicles[site.Name] = map[string]int{}
+		}
+		s.Launches++
+		switch outcome {
+		case parse.OutcomeSuccess:
+			s.Successes++
+		case parse.OutcomeLaunchFailure, parse.OutcomePartialLaunchFailure:
+			s.Failures++
+		}
+
+		date := launch.Timestamp.DateString()
+		if s.FirstLaunch == "" || date < s.FirstLaunch {
+			s.FirstLaunch = date
+		}
+		if date > s.LastLaunch {
+			s.LastLaunch = date
+		}
+		if vehicle := strings.TrimSpace(launch.Rocket); vehicle != "" {
+			vehicles[site.Name][vehicle]++
+		}
+	}
+
+	for name, s := range stats {
+		if known := s.Successes + s.Failures; known > 0 {
+			s.SuccessRate = float64(s.Successes) / float64(known)
+		}
+		for vehicle, count := range vehicles[name] {
+			s.TopVehicles = append(s.TopVehicles, VehicleCount{vehicle, count})
+		}
+		sort.Slice(s.TopVehicles, func(i, j int) bool {
+			a, b := s.TopVehicles[i], s.TopVehicles[j]
+			if a.Launches != b.Launches {
+				return a.Launches > b.Launches
+			}
+			return a.Vehicle < b.Vehicle
+		})
+		if len(s.TopVehicles) > 3 {
+			s.TopVehicles = s.TopVehicles[:3]
+		}
+		summary.Sites = append(summary.Sites, *s)
+	}
+	sort.Slice(summary.Sites, func(i, j int) bool {
+		a, b := summary.Sites[i], summary.Sites[j]
+		if a.Launches != b.Launches {
+			return a.Launches > b.Launches
+		}
+		return a.Name < b.Name
+	})
+	return summary
+}
@@ -0,0 +1,101 @@
+package sites
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"launchdata/parse"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultGazetteer(t *testing.T) {
+	g, err := Default()
+	require.NoError(t, err)
+	assert.Greater(t, len(g.Sites), 30)
+
+	tests := map[string]string{
+		"Baikonur Site 1/5":           "Baikonur Cosmodrome",
+		"Cape Canaveral SLC-40":       "Cape Canaveral Space Force Station",
+		"Kennedy LC-39A":              "Kennedy Space Center",
+		"  Plesetsk   Site 43/4":      "Plesetsk Cosmodrome",
+		"Pleetsk Site 41/1":           "Plesetsk Cosmodrome",
+		"MARS LP-0A":                  "Mid-Atlantic Regional Spaceport",
+		"Māhia LC-1A":                 "Rocket Lab Launch Complex 1",
+		"Jiuquan SLS-2":               "Jiuquan Satellite Launch Center",
+		"Kourou ELA-3":                "Guiana Space Centre",
+		"Kapustin Yar Site 107":       "Kapustin Yar",
+		"Satish Dhawan Second Pad":    "Satish Dhawan Space Centre",
+		"Uchinoura Mu Pad, Kagoshima": "Uchinoura Space Center",
+	}
+	for launchSite, want := range tests {
+		site, ok := g.Locate(launchSite)
+		require.True(t, ok, launchSite)
+		assert.Equal(t, want, site.Name, launchSite)
+	}
+
+	for _, launchSite := range []string{"Ocean Odyssey", "Stargazer, Vandenberg", "Marshall", ""} {
+		_, ok := g.Locate(launchSite)
+		assert.False(t, ok, launchSite)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		"empty":         "",
+		"bad latitude":  "name,country,latitude,longitude,aliases\nNowhere,Nowhere,91,0,Nowhere\n",
+		"bad longitude": "name,country,latitude,longitude,aliases\nNowhere,Nowhere,0,east,Nowhere\n",
+		"no aliases":    "name,country,latitude,longitude,aliases\nNowhere,Nowhere,0,0,\n",
+		"short row":     "name,country,latitude,longitude,aliases\nNowhere,Nowhere,0,0\n",
+	}
+	for name, contents := range tests {
+		filename := filepath.Join(dir, name+".csv")
+		require.NoError(t, os.WriteFile(filename, []byte(contents), 0o644))
+		_, err := Load(filename)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestSummarise(t *testing.T) {
+	g, err := Default()
+	require.NoError(t, err)
+
+	launch := func(day int, rocket string, site string, outcome string) parse.RocketData {
+		return parse.RocketData{
+			Id:         rocket + site,
+			Timestamp:  parse.TimeData{Timestamp: time.Date(2022, 1, day, 12, 0, 0, 0, time.UTC), ParsedOk: true},
+			Rocket:     rocket,
+			LaunchSite: site,
+			Payload:    []parse.PayloadData{{Payload: "Payload", Outcome: outcome}},
+		}
+	}
+	launches := []parse.RocketData{
+		launch(3, "Soyuz-2.1a", "Baikonur Site 31/6", "Successful"),
+		launch(9, "Soyuz-2.1b", "Baikonur Site 31/6", "Launch failure"),
+		launch(5, "Soyuz-2.1a", "Baikonur Site 31/6", "Successful"),
+		launch(7, "Proton-M", "Baikonur Site 200/39", "Unknown"),
+		launch(20, "Soyuz-2.1a", "Baikonur Site 31/6", "Planned"),
+		launch(4, "Falcon 9", "Kennedy LC-39A", "Successful"),
+		launch(6, "Pegasus", "Stargazer, Vandenberg", "Successful"),
+	}
+
+	summary := g.Summarise(launches)
+	require.Len(t, summary.Sites, 2)
+	baikonur := summary.Sites[0]
+	assert.Equal(t, "Baikonur Cosmodrome", baikonur.Name)
+	assert.Equal(t, 4, baikonur.Launches, "the planned launch isn't counted")
+	assert.Equal(t, 2, baikonur.Successes)
+	assert.Equal(t, 1, baikonur.Failures)
+	assert.InDelta(t, 2.0/3, baikonur.SuccessRate, 0.0001)
+	assert.Equal(t, "2022-01-03", baikonur.FirstLaunch)
+	assert.Equal(t, "2022-01-09", baikonur.LastLaunch)
+	assert.Equal(t, []VehicleCount{{"Soyuz-2.1a", 2}, {"Proton-M", 1}, {"Soyuz-2.1b", 1}}, baikonur.TopVehicles)
+
+	assert.Equal(t, "Kennedy Space Center", summary.Sites[1].Name)
+	assert.Len(t, summary.Launches, 5)
+	assert.Equal(t, map[string]int{"Stargazer, Vandenberg": 1}, summary.Unlocated)
+}
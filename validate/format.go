@@ -0,0 +1,130 @@
+package validate
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"launchdata/jsonio"
+)
+
+const (
+	FormatText  = "text"
+	FormatJson  = "json"
+	FormatSarif = "sarif"
+)
+
+var Formats = []string{FormatText, FormatJson, FormatSarif}
+
+func Write(w io.Writer, format string, findings []Finding) error {
+	switch format {
+	case FormatText:
+		return writeText(w, findings)
+	case FormatJson:
+		return writeJson(w, findings)
+	case FormatSarif:
+		return writeJson(w, sarifLog(findings))
+	}
+	return fmt.Errorf("unknown format %q, expected one of %s", format, strings.Join(Formats, ", "))
+}
+
+func writeText(w io.Writer, findings []Finding) error {
+	counts := map[Severity]int{}
+	for _, f := range findings {
+		fmt.Fprintf(w, "%s: %s [%s] %s\n", f.Filename, f.Severity, f.Rule, f.Message)
+		counts[f.Severity]++
+	}
+	fmt.Fprintf(w, "%d errors, %d warnings, %d notes\n", counts[SeverityError], counts[SeverityWarning], counts[SeverityNote])
+	return nil
+}
+
+func writeJson(w io.Writer, contents interface{}) error {
+	formatted, err := jsonio.FormattedJson(contents)
+	if err != nil {
+		return err
+	}
+	_, err = formatted.WriteTo(w)
+	return err
+}
+
+// The subset of SARIF 2.1.0 that code scanning tools need to show findings
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifRule struct {
+	Id                   string       `json:"id"`
+	ShortDescription     sarifMessage `json:"shortDescription"`
+	DefaultConfiguration struct {
+		Level Severity `json:"level"`
+	} `json:"defaultConfiguration"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			Uri string `json:"uri"`
+		} `json:"artifactLocation"`
+	} `json:"physicalLocation"`
+	LogicalLocations []struct {
+		FullyQualifiedName string `json:"fullyQualifiedName"`
+	} `json:"logicalLocations,omitempty"`
+}
+
+type sarifResult struct {
+	RuleId    string          `json:"ruleId"`
+	Level     Severity        `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifRun struct {
+	Tool struct {
+		Driver struct {
+			Name  string      `json:"name"`
+			Rules []sarifRule `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarif struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+func sarifLog(findings []Finding) sarif {
+	var run sarifRun
+	run.Tool.Driver.Name = "launchdata validate"
+	for _, rule := range Rules {
+		r := sarifRule{Id: rule.Name, ShortDescription: sarifMessage{rule.Description}}
+		r.DefaultConfiguration.Level = rule.Severity
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, r)
+	}
+
+	run.Results = []sarifResult{}
+	for _, f := range findings {
+		var location sarifLocation
+		location.PhysicalLocation.ArtifactLocation.Uri = f.Filename
+		if f.LaunchId != "" {
+			location.LogicalLocations = append(location.LogicalLocations, struct {
+				FullyQualifiedName string `json:"fullyQualifiedName"`
+			}{f.LaunchId})
+		}
+
+		run.Results = append(run.Results, sarifResult{
+			RuleId:    f.Rule,
+			Level:     f.Severity,
+			Message:   sarifMessage{f.Message},
+			Locations: []sarifLocation{location},
+		})
+	}
+
+	return sarif{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+}
@@ -0,0 +1,179 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"launchdata/parse"
+)
+
+// Rules is every rule we know about, with its default severity
+var Rules = []Rule{
+	{
+		Name:        "no-payload",
+		Description: "A launch that has already happened has no payloads",
+		Severity:    SeverityWarning,
+		Check:       checkNoPayload,
+	},
+	{
+		Name:        "unparsed-past-timestamp",
+		Description: "A launch in a past year has a timestamp we couldn't parse",
+		Severity:    SeverityWarning,
+		Check:       checkUnparsedPastTimestamp,
+	},
+	{
+		Name:        "timestamp-outside-year",
+		Description: "A launch's timestamp isn't in the year of the file it's in",
+		Severity:    SeverityError,
+		Check:       checkTimestampOutsideYear,
+	},
+	{
+		Name:        "duplicate-flight-number",
+		Description: "Two launches of the same rocket share a flight number",
+		Severity:    SeverityError,
+		Check:       checkDuplicateFlightNumber,
+	},
+	{
+		Name:        "unknown-outcome",
+		Description: "A payload of a past launch has an outcome we don't recognise",
+		Severity:    SeverityNote,
+		Check:       checkUnknownOutcome,
+	},
+	{
+		Name:        "blank-rocket-or-site",
+		Description: "A launch has no rocket or no launch site",
+		Severity:    SeverityError,
+		Check:       checkBlankRocketOrSite,
+	},
+	{
+		Name:        "notes-as-payload",
+		Description: "A payload row looks like it was really a notes row",
+		Severity:    SeverityError,
+		Check:       checkNotesAsPayload,
+	},
+}
+
+func launchFinding(launch parse.RocketData, format string, args ...interface{}) Finding {
+	return Finding{
+		LaunchId: launch.Id,
+		Message:  fmt.Sprintf("%s %s: %s", launch.Timestamp.DateString(), strings.TrimSpace(launch.Rocket), fmt.Sprintf(format, args...)),
+	}
+}
+
+func checkNoPayload(file File, now time.Time) []Finding {
+	var findings []Finding
+	for _, launch := range file.Data.OrbitalFlights {
+		if len(launch.Payload) == 0 && launch.Timestamp.LaunchedAlready(now) {
+			findings = append(findings, launchFinding(launch, "no payloads"))
+		}
+	}
+	return findings
+}
+
+func checkUnparsedPastTimestamp(file File, now time.Time) []Finding {
+	if file.Year >= now.Year() {
+		// Vague timestamps are expected for launches that haven't happened yet
+		return nil
+	}
+
+	var findings []Finding
+	for _, launch := range file.Data.OrbitalFlights {
+		if !launch.Timestamp.ParsedOk {
+			findings = append(findings, launchFinding(launch, "couldn't parse timestamp %q", launch.Timestamp.TimestampClean))
+		}
+	}
+	return findings
+}
+
+func checkTimestampOutsideYear(file File, now time.Time) []Finding {
+	var findings []Finding
+	for _, launch := range file.Data.OrbitalFlights {
+		if launch.Timestamp.ParsedOk && launch.Timestamp.Timestamp.Year() != file.Year {
+			findings = append(findings, launchFinding(launch, "timestamp %s is outside %d", launch.Timestamp.TimeString(), file.Year))
+		}
+	}
+	return findings
+}
+
+func checkDuplicateFlightNumber(file File, now time.Time) []Finding {
+	var findings []Finding
+	seen := map[string]bool{}
+	for _, launch := range file.Data.OrbitalFlights {
+		flightNumber := strings.TrimSpace(launch.FlightNumber)
+		if flightNumber == "" {
+			continue
+		}
+
+		key := strings.TrimSpace(launch.Rocket) + "\x00" + flightNumber
+		if seen[key] {
+			findings = append(findings, launchFinding(launch, "flight number %q is used more than once", flightNumber))
+		}
+		seen[key] = true
+	}
+	return findings
+}
+
+func checkUnknownOutcome(file File, now time.Time) []Finding {
+	var findings []Finding
+	for _, launch := range file.Data.OrbitalFlights {
+		if !launch.Timestamp.LaunchedAlready(now) {
+			continue
+		}
+		for _, payload := range launch.Payload {
+			if parse.ClassifyOutcome(payload.Outcome) == parse.OutcomeUnknown {
+				findings = append(findings, launchFinding(launch, "payload %q has unknown outcome %q", strings.TrimSpace(payload.Payload), payload.Outcome))
+			}
+		}
+	}
+	return findings
+}
+
+func checkBlankRocketOrSite(file File, now time.Time) []Finding {
+	var findings []Finding
+	for _, launch := range file.Data.OrbitalFlights {
+		if strings.TrimSpace(launch.Rocket) == "" {
+			findings = append(findings, launchFinding(launch, "no rocket"))
+		}
+		if strings.TrimSpace(launch.LaunchSite) == "" {
+			findings = append(findings, launchFinding(launch, "no launch site"))
+		}
+	}
+	return findings
+}
+
+// Notes rows repeat the same text across every column, so when one is
+// mistaken for a payload most of the payload's fields are identical
+func looksLikeNotes(payload parse.PayloadData) bool {
+	fields := []string{payload.Payload, payload.Operator, payload.Orbit, payload.Function, payload.Decay, payload.Outcome}
+	counts := map[string]int{}
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			counts[field]++
+		}
+	}
+	for _, count := range counts {
+		if count >= 3 {
+			return true
+		}
+	}
+
+	// Payload names are short, notes are sentences
+	return len(payload.Payload) > 150
+}
+
+func checkNotesAsPayload(file File, now time.Time) []Finding {
+	var findings []Finding
+	for _, launch := range file.Data.OrbitalFlights {
+		for _, payload := range launch.Payload {
+			if looksLikeNotes(payload) {
+				name := []rune(strings.TrimSpace(payload.Payload))
+				if len(name) > 60 {
+					name = append(name[:60], '…')
+				}
+				findings = append(findings, launchFinding(launch, "payload %q looks like a notes row", string(name)))
+			}
+		}
+	}
+	return findings
+}
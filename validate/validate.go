@@ -0,0 +1,161 @@
+// Package validate runs a set of rules over the cached launch data, looking
+// for the kinds of mistakes the parser tends to make, so that regressions in
+// the data can fail a pipeline instead of going unnoticed.
+package validate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"launchdata/dataset"
+	"launchdata/parse"
+)
+
+// Severity levels match SARIF's, so they can be passed straight through
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityNote    Severity = "note"
+	SeverityOff     Severity = "off"
+)
+
+var severityRank = map[Severity]int{
+	SeverityOff:     0,
+	SeverityNote:    1,
+	SeverityWarning: 2,
+	SeverityError:   3,
+}
+
+func ParseSeverity(s string) (Severity, error) {
+	severity := Severity(strings.ToLower(s))
+	if _, ok := severityRank[severity]; !ok {
+		return severity, fmt.Errorf("unknown severity %q, expected error, warning, note or off", s)
+	}
+	return severity, nil
+}
+
+// AtLeast reports whether s is as severe as other
+func (s Severity) AtLeast(other Severity) bool {
+	return severityRank[s] >= severityRank[other]
+}
+
+// File is a single cached year, which is what rules are run against
+type File struct {
+	Year     int
+	Filename string
+	Data     parse.AllLaunchData
+}
+
+type Finding struct {
+	Rule     string
+	Severity Severity
+	Filename string
+	Year     int
+	LaunchId string `json:",omitempty"`
+	Message  string
+}
+
+type Rule struct {
+	Name        string
+	Description string
+	Severity    Severity
+
+	// Check returns a finding for each problem in file. Rule and Severity are
+	// filled in by the caller.
+	Check func(file File, now time.Time) []Finding
+}
+
+// Config picks which rules to run and how severe their findings are
+type Config struct {
+	// Rules to run, all of them if empty
+	Rules []string
+
+	// Severities overrides the default severity of rules by name
+	Severities map[string]Severity
+}
+
+func (c Config) rules() ([]Rule, error) {
+	byName := map[string]Rule{}
+	for _, rule := range Rules {
+		byName[rule.Name] = rule
+	}
+
+	for name := range c.Severities {
+		if _, ok := byName[name]; !ok {
+			return nil, fmt.Errorf("unknown rule %q", name)
+		}
+	}
+
+	selected := Rules
+	if len(c.Rules) > 0 {
+		selected = nil
+		for _, name := range c.Rules {
+			rule, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown rule %q", name)
+			}
+			selected = append(selected, rule)
+		}
+	}
+
+	var rules []Rule
+	for _, rule := range selected {
+		if severity, ok := c.Severities[rule.Name]; ok {
+			rule.Severity = severity
+		}
+		if rule.Severity != SeverityOff {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// Run checks each file against the configured rules
+func Run(config Config, files []File, now time.Time) ([]Finding, error) {
+	rules, err := config.rules()
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	for _, file := range files {
+		for _, rule := range rules {
+			for _, finding := range rule.Check(file, now) {
+				finding.Rule = rule.Name
+				finding.Severity = rule.Severity
+				finding.Filename = file.Filename
+				finding.Year = file.Year
+				findings = append(findings, finding)
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Year < findings[j].Year
+	})
+
+	return findings, nil
+}
+
+// LoadDir reads every year cached in dir
+func LoadDir(dir string, options parse.LoadOptions) ([]File, error) {
+	years, err := dataset.Years(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []File
+	for _, year := range years {
+		filename := dataset.Filename(dir, year)
+		data, err := parse.LoadLaunchData(filename, options)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Year: year, Filename: filename, Data: data})
+	}
+	return files, nil
+}
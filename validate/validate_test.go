@@ -0,0 +1,107 @@
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"launchdata/parse"
+)
+
+var now = time.Date(2022, 9, 1, 0, 0, 0, 0, time.UTC)
+
+func at(t time.Time) parse.TimeData {
+	return parse.TimeData{Timestamp: t, ParsedOk: true, TimestampClean: t.Format("2 January")}
+}
+
+func testFile() File {
+	payload := parse.PayloadData{Payload: "Starlink × 49", Operator: "SpaceX", Orbit: "Low Earth", Outcome: "Operational"}
+	notes := parse.PayloadData{Payload: "Dedicated rideshare", Operator: "Dedicated rideshare", Orbit: "Dedicated rideshare", Outcome: "Dedicated rideshare"}
+
+	launches := []parse.RocketData{
+		{Id: "ok", Rocket: "Falcon 9", LaunchSite: "LC-39A", FlightNumber: "F9-1", Timestamp: at(time.Date(2022, 1, 6, 0, 0, 0, 0, time.UTC)), Payload: []parse.PayloadData{payload}},
+		{Id: "empty", Rocket: "Falcon 9", LaunchSite: "LC-39A", Timestamp: at(time.Date(2022, 1, 7, 0, 0, 0, 0, time.UTC))},
+		{Id: "wrong-year", Rocket: "Falcon 9", LaunchSite: "LC-39A", Timestamp: at(time.Date(2021, 1, 7, 0, 0, 0, 0, time.UTC)), Payload: []parse.PayloadData{payload}},
+		{Id: "duplicate", Rocket: "Falcon 9", LaunchSite: "LC-39A", FlightNumber: "F9-1", Timestamp: at(time.Date(2022, 1, 8, 0, 0, 0, 0, time.UTC)), Payload: []parse.PayloadData{payload}},
+		{Id: "blank", Timestamp: at(time.Date(2022, 1, 9, 0, 0, 0, 0, time.UTC)), Payload: []parse.PayloadData{payload}},
+		{Id: "notes", Rocket: "Falcon 9", LaunchSite: "LC-39A", Timestamp: at(time.Date(2022, 1, 10, 0, 0, 0, 0, time.UTC)), Payload: []parse.PayloadData{notes}},
+		{Id: "upcoming", Rocket: "Falcon 9", LaunchSite: "LC-39A", Timestamp: parse.TimeData{TimestampClean: "Late 2022"}},
+	}
+
+	return File{Year: 2022, Filename: "launchdata-2022.json", Data: parse.AllLaunchData{OrbitalFlights: launches}}
+}
+
+func findingIds(findings []Finding) map[string][]string {
+	got := map[string][]string{}
+	for _, f := range findings {
+		got[f.Rule] = append(got[f.Rule], f.LaunchId)
+	}
+	return got
+}
+
+func TestRules(t *testing.T) {
+	findings, err := Run(Config{}, []File{testFile()}, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]string{
+		"no-payload":              {"empty"},
+		"timestamp-outside-year":  {"wrong-year"},
+		"duplicate-flight-number": {"duplicate"},
+		"blank-rocket-or-site":    {"blank", "blank"},
+		"notes-as-payload":        {"notes"},
+		"unknown-outcome":         {"notes"},
+	}, findingIds(findings))
+
+	// Vague timestamps only matter once the year is over
+	file := testFile()
+	file.Year = 2021
+	findings, err = Run(Config{Rules: []string{"unparsed-past-timestamp"}}, []File{file}, now)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"unparsed-past-timestamp": {"upcoming"}}, findingIds(findings))
+}
+
+func TestConfig(t *testing.T) {
+	config := Config{
+		Rules:      []string{"no-payload", "notes-as-payload"},
+		Severities: map[string]Severity{"no-payload": SeverityError, "notes-as-payload": SeverityOff},
+	}
+	findings, err := Run(config, []File{testFile()}, now)
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "no-payload", findings[0].Rule)
+	assert.Equal(t, SeverityError, findings[0].Severity)
+
+	_, err = Run(Config{Rules: []string{"no-such-rule"}}, nil, now)
+	assert.Error(t, err)
+	_, err = Run(Config{Severities: map[string]Severity{"no-such-rule": SeverityNote}}, nil, now)
+	assert.Error(t, err)
+}
+
+func TestSarifOutput(t *testing.T) {
+	findings, err := Run(Config{Rules: []string{"no-payload"}}, []File{testFile()}, now)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatSarif, findings))
+
+	var got sarif
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	require.Len(t, got.Runs[0].Results, 1)
+	result := got.Runs[0].Results[0]
+	assert.Equal(t, "no-payload", result.RuleId)
+	assert.Equal(t, SeverityWarning, result.Level)
+	assert.Equal(t, "launchdata-2022.json", result.Locations[0].PhysicalLocation.ArtifactLocation.Uri)
+	assert.Equal(t, "empty", result.Locations[0].LogicalLocations[0].FullyQualifiedName)
+}
+
+func TestSeverity(t *testing.T) {
+	assert.True(t, SeverityError.AtLeast(SeverityWarning))
+	assert.False(t, SeverityNote.AtLeast(SeverityWarning))
+
+	_, err := ParseSeverity("fatal")
+	assert.Error(t, err)
+}